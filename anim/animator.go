@@ -1,99 +1,148 @@
 package anim
 
-import (
-	"fmt"
-
-	"github.com/go-gl/mathgl/mgl32"
-)
-
+// NewAnimator builds an animator with a rig of its own, use a Rig to share
+// the skeleton and clips between many animators
 func NewAnimator(skeleton *Skeleton, animations []Animation) (*Animator, error) {
-	//Error testing
-	boneCount := len(skeleton.Bones)
-	for a := range animations {
-		for b := range animations[a].Keyframes {
-			if len(animations[a].Keyframes[b].Transforms) != boneCount {
-				return nil, fmt.Errorf("anim: Incompatible keyframe %v of animation %v, expected %v, but go %v transforms", b, a, boneCount, len(animations[a].Keyframes[b].Transforms))
-			}
-		}
-	}
-	a := Animator{skeleton: skeleton, animations: animations}
-	a.animationStates = make([]animationState, len(animations))
-	for i := range a.animationStates {
-		a.animationStates[i].loop = true
-		a.animationStates[i].playbackRate = 1
-	}
-	a.workingPoses = make([]Keyframe, 2)
-	for i := range a.workingPoses {
-		a.workingPoses[i].Transforms = make([]Transform, boneCount)
+	rig, err := NewRig(skeleton, animations)
+	if err != nil {
+		return nil, err
 	}
-	a.localPose.Transforms = make([]Transform, boneCount)
-	a.localPoseMatrices = make([]mgl32.Mat4, boneCount)
-	a.GlobalPoseMatrices = make([]mgl32.Mat4, boneCount)
-	a.globalPosesSet = make([]bool, boneCount)
-	return &a, nil
+	return rig.NewAnimator(), nil
 }
 
+// CalcGlobalPoseMatrices computes the skinning matrices of the local pose,
+// parents before their children
 func (a *Animator) CalcGlobalPoseMatrices() {
-	for i := range a.skeleton.Bones {
-		a.globalPosesSet[i] = false
+	calcSkinningMatrices(a.skeleton, a.order, &a.localPose, a.GlobalPoseMatrices)
+}
+
+// Update advances the animator's clock and evaluates its blend tree into the
+// global pose matrices and their dual quaternions
+func (a *Animator) Update(deltaTime float32) {
+	deltaTime *= a.timeScale
+	a.baseClock.advance(deltaTime)
+	for _, layerClock := range a.layerClocks {
+		layerClock.advance(deltaTime)
 	}
-	for i := range a.localPoseMatrices {
-		boneSpacePose := TransformToMat4(a.localPose.Transforms[i])
-		a.localPoseMatrices[i] = a.skeleton.Bones[i].BindPose.Mul4(boneSpacePose.Mul4(a.skeleton.Bones[i].InverseBindPose))
+	a.frame++
+	a.events = a.events[:0]
+	a.rootMotion = RootMotion{}
+	//Triggers only live for one update, one no transition fired on is stale
+	defer a.clearTriggers()
+	if a.blendTree == nil {
+		return
 	}
-	for i := range a.skeleton.Bones {
-		_ = a.calcGlobalPoseMatrix(i)
+	a.blendTree.evaluate(a, 0, 1)
+	if a.rootMotionEnabled {
+		root := a.workingPoses[0].transform(a.skeleton.RootIndex)
+		a.removeRootMotion(&root)
+		a.workingPoses[0].setTransform(a.skeleton.RootIndex, root)
 	}
+	a.applyLayers()
+	a.localPose = a.workingPoses[0]
+	a.CalcGlobalPoseMatrices()
+	for _, processor := range a.postProcessors {
+		processor.Apply(a)
+	}
+	a.CalcGlobalDualQuaternions()
+}
+
+// PostProcessor adjusts the global pose matrices after the animator computed
+// them, like an IK solver
+type PostProcessor interface {
+	Apply(a *Animator)
 }
 
-func (a *Animator) calcGlobalPoseMatrix(boneIndex int) mgl32.Mat4 {
-	if boneIndex == a.skeleton.RootIndex {
-		a.GlobalPoseMatrices[boneIndex] = a.localPoseMatrices[boneIndex]
-	} else if !a.globalPosesSet[boneIndex] {
-		a.GlobalPoseMatrices[boneIndex] = a.calcGlobalPoseMatrix(a.skeleton.Bones[boneIndex].ParentIndex).Mul4(a.localPoseMatrices[boneIndex])
+// AddPostProcessor appends a step that runs at the end of every Update, in
+// the order the steps were added
+func (a *Animator) AddPostProcessor(processor PostProcessor) {
+	a.postProcessors = append(a.postProcessors, processor)
+}
 
+func (a *Animator) Skeleton() *Skeleton {
+	return a.skeleton
+}
+
+// SetBlendTree replaces the tree evaluated by Update and sizes the pose pool
+// to fit it
+func (a *Animator) SetBlendTree(root BlendNode) {
+	a.blendTree = root
+	a.resizePosePool()
+}
+
+func (a *Animator) resizePosePool() {
+	count := 0
+	if a.blendTree != nil {
+		count = a.blendTree.poseCount()
+	}
+	for i := range a.layers {
+		count = maxInt(count, 1+a.layers[i].Node.poseCount())
 	}
-	a.globalPosesSet[boneIndex] = true
-	return a.GlobalPoseMatrices[boneIndex]
+	a.reservePoses(count)
 }
 
-func (a *Animator) Update(deltaTime float32, userFunc func()) {
-	a.globalTime += deltaTime
-	userFunc()
-	a.localPose = a.workingPoses[0]
-	a.CalcGlobalPoseMatrices()
+func (a *Animator) reservePoses(count int) {
+	for len(a.workingPoses) < count {
+		a.workingPoses = append(a.workingPoses, newPose(len(a.skeleton.Bones)))
+	}
 }
 
-func (a *Animator) SampleLinear(sampleIndex int, t float32, resultIndex int) {
-	a.animations[sampleIndex].linearSample(t, &a.workingPoses[resultIndex])
+func (a *Animator) SetParameter(name string, value float32) {
+	a.parameters[name] = value
 }
 
-func (a *Animator) LinearBlend(firstIndex, secondIndex int, t float32, resultIndex int) {
-	lerpKeyframe(&a.workingPoses[firstIndex], &a.workingPoses[secondIndex], t, &a.workingPoses[resultIndex])
+func (a *Animator) Parameter(name string) float32 {
+	return a.parameters[name]
 }
 
-func (a *Animator) AdditiveBlend(baseIndex, additiveIndex int, t float32, resultIndex int) {
-	for i := 0; i < len(a.workingPoses[baseIndex].Transforms); i++ {
-		addTransforms(&a.workingPoses[baseIndex].Transforms[i], &a.workingPoses[additiveIndex].Transforms[i], t, &a.workingPoses[resultIndex].Transforms[i])
+// sampleAtParameter samples an animation at a time given by a parameter,
+// events are only fired if it was also sampled on the previous frame
+func (a *Animator) sampleAtParameter(sampleIndex int, t float32, resultIndex int, weight float32) {
+	state := &a.animationStates[sampleIndex]
+	if state.sampledFrame == a.frame-1 {
+		a.fireEvents(sampleIndex, state.sampledTime, t, LoopClamp, weight)
+		a.accumulateRootMotion(sampleIndex, state.sampledTime, t, LoopClamp, weight)
 	}
+	state.sampledFrame, state.sampledTime = a.frame, t
+	a.animations[sampleIndex].sample(t, &a.workingPoses[resultIndex])
+}
+
+func (a *Animator) linearBlend(firstIndex, secondIndex int, t float32, resultIndex int) {
+	lerpPose(&a.workingPoses[firstIndex], &a.workingPoses[secondIndex], t, &a.workingPoses[resultIndex])
+}
+
+func (a *Animator) additiveBlend(baseIndex, additiveIndex int, t float32, resultIndex int) {
+	addPose(&a.workingPoses[baseIndex], &a.workingPoses[additiveIndex], t, &a.workingPoses[resultIndex])
 }
 
-func (a *Animator) SampleAtGlobalTime(sampleIndex, resultIndex int) {
+// sampleAtGlobalTime samples an animation by its own clock, see Play
+func (a *Animator) sampleAtGlobalTime(sampleIndex, resultIndex int, weight float32) {
 	state := &a.animationStates[sampleIndex]
 	animation := &a.animations[sampleIndex]
-	t := state.playbackRate * (a.globalTime - state.startTime)
-	if state.loop {
-		t = t - animation.Duration*float32(int(t/(animation.Duration)))
-	} else if t > animation.Duration {
-		t = animation.Duration
-	}
-	animation.linearSample(t, &a.workingPoses[resultIndex])
+	//Events between the clip's times of the last frame and this one
+	previous, t := a.advanceClip(sampleIndex)
+	a.fireEvents(sampleIndex, previous, t, state.loopMode, weight)
+	a.accumulateRootMotion(sampleIndex, previous, t, state.loopMode, weight)
+	animation.sample(state.loopMode.wrap(t, animation.Duration), &a.workingPoses[resultIndex])
 }
 
+// SetPlaybackRate sets how fast a clip plays, negative rates play it in
+// reverse
 func (a *Animator) SetPlaybackRate(index int, rate float32) {
+	a.settle(index)
 	a.animationStates[index].playbackRate = rate
 }
 
+// SetLooping switches a clip between LoopRepeat and LoopClamp
 func (a *Animator) SetLooping(index int, loop bool) {
-	a.animationStates[index].loop = loop
+	if loop {
+		a.SetLoopMode(index, LoopRepeat)
+	} else {
+		a.SetLoopMode(index, LoopClamp)
+	}
+}
+
+func (a *Animator) SetLoopMode(index int, mode LoopMode) {
+	a.settle(index)
+	a.animationStates[index].loopMode = mode
 }
@@ -1,13 +1,14 @@
 package anim
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-var first = Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}
-var second = Transform{[3]float32{1, 1, 1}, [3]float32{0, 0.025, 0}, [3]float32{0, 0, 0}}
+var first = TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})
+var second = TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0.025, 0}, [3]float32{0, 0, 30})
 
 var one = mgl32.Vec3{1, 2, 3}
 var two = mgl32.Vec3{2, 3, 4}
@@ -31,9 +32,10 @@ func BenchmarkInlineExpanded(b *testing.B) {
 		result.Translate[0] = first.Translate[0]*(1-t) + second.Translate[0]*t
 		result.Translate[1] = first.Translate[1]*(1-t) + second.Translate[1]*t
 		result.Translate[2] = first.Translate[2]*(1-t) + second.Translate[2]*t
-		result.Rotation[0] = first.Rotation[0]*(1-t) + second.Rotation[0]*t
-		result.Rotation[1] = first.Rotation[1]*(1-t) + second.Rotation[1]*t
-		result.Rotation[2] = first.Rotation[2]*(1-t) + second.Rotation[2]*t
+		result.Rotation.W = first.Rotation.W*(1-t) + second.Rotation.W*t
+		result.Rotation.V[0] = first.Rotation.V[0]*(1-t) + second.Rotation.V[0]*t
+		result.Rotation.V[1] = first.Rotation.V[1]*(1-t) + second.Rotation.V[1]*t
+		result.Rotation.V[2] = first.Rotation.V[2]*(1-t) + second.Rotation.V[2]*t
 		result.Scale[0] = first.Scale[0]*(1-t) + second.Scale[0]*t
 		result.Scale[1] = first.Scale[1]*(1-t) + second.Scale[1]*t
 		result.Scale[2] = first.Scale[2]*(1-t) + second.Scale[2]*t
@@ -79,3 +81,149 @@ func BenchmarkZeroingInline(b *testing.B) {
 		three[2] = 0
 	}
 }
+
+func BenchmarkSampleLongClip(b *testing.B) {
+	const boneCount, keyCount = 30, 2000
+	times := make([]float32, keyCount)
+	transforms := make([]Transform, keyCount)
+	for k := range times {
+		times[k] = float32(k) / 120
+		transforms[k] = TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, float32(k % 7), 0}, [3]float32{float32(k % 90), 0, float32(k % 45)})
+	}
+	animation := Animation{Duration: times[keyCount-1]}
+	for bone := 0; bone < boneCount; bone++ {
+		animation.Tracks = append(animation.Tracks, NewTrack(bone, times, transforms))
+	}
+	pose := newPose(boneCount)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		animation.sample(float32(i%keyCount)/120+0.004, &pose)
+	}
+}
+
+// legacyPose is the array of structures pose with the recursive global pose
+// computation the animator used before poses were stored as arrays, kept to
+// measure the new layout against
+type legacyPose struct {
+	skeleton           *Skeleton
+	localPose          Keyframe
+	localPoseMatrices  []mgl32.Mat4
+	globalPoseMatrices []mgl32.Mat4
+	globalPosesSet     []bool
+}
+
+func newLegacyPose(skeleton *Skeleton) *legacyPose {
+	boneCount := len(skeleton.Bones)
+	return &legacyPose{skeleton: skeleton, localPose: Keyframe{Transforms: make([]Transform, boneCount)},
+		localPoseMatrices: make([]mgl32.Mat4, boneCount), globalPoseMatrices: make([]mgl32.Mat4, boneCount), globalPosesSet: make([]bool, boneCount)}
+}
+
+func (l *legacyPose) calcGlobalPoseMatrices() {
+	for i := range l.skeleton.Bones {
+		l.globalPosesSet[i] = false
+	}
+	for i := range l.localPoseMatrices {
+		boneSpacePose := TransformToMat4(l.localPose.Transforms[i])
+		l.localPoseMatrices[i] = l.skeleton.Bones[i].BindPose.Mul4(boneSpacePose.Mul4(l.skeleton.Bones[i].InverseBindPose))
+	}
+	for i := range l.skeleton.Bones {
+		_ = l.calcGlobalPoseMatrix(i)
+	}
+}
+
+func (l *legacyPose) calcGlobalPoseMatrix(boneIndex int) mgl32.Mat4 {
+	if boneIndex == l.skeleton.RootIndex {
+		l.globalPoseMatrices[boneIndex] = l.localPoseMatrices[boneIndex]
+	} else if !l.globalPosesSet[boneIndex] {
+		l.globalPoseMatrices[boneIndex] = l.calcGlobalPoseMatrix(l.skeleton.Bones[boneIndex].ParentIndex).Mul4(l.localPoseMatrices[boneIndex])
+	}
+	l.globalPosesSet[boneIndex] = true
+	return l.globalPoseMatrices[boneIndex]
+}
+
+func legacyLerpKeyframe(first, second *Keyframe, t float32, result *Keyframe) {
+	for i := 0; i < len(first.Transforms); i++ {
+		lerpTransform(&first.Transforms[i], &second.Transforms[i], t, &result.Transforms[i])
+	}
+}
+
+const benchBoneCount = 64
+
+// benchSkeleton is a binary tree of bones listed children first, the worst
+// order for the recursive computation
+func benchSkeleton() *Skeleton {
+	skeleton := &Skeleton{Bones: make([]Bone, benchBoneCount), RootIndex: benchBoneCount - 1}
+	for i := range skeleton.Bones {
+		node := benchBoneCount - 1 - i
+		bindPose := mgl32.Translate3D(float32(node%3), float32(node), 0)
+		skeleton.Bones[i] = Bone{Name: fmt.Sprint(i), BindPose: bindPose, InverseBindPose: bindPose.Inv(), ParentIndex: benchBoneCount - 1 - (node-1)/2, Index: i}
+	}
+	skeleton.Bones[skeleton.RootIndex].ParentIndex = -1
+	return skeleton
+}
+
+func benchKeyframe(offset float32) Keyframe {
+	keyframe := Keyframe{Transforms: make([]Transform, benchBoneCount)}
+	for i := range keyframe.Transforms {
+		keyframe.Transforms[i] = TransformFromEuler([3]float32{1, 1, 1}, [3]float32{offset, 0, 0}, [3]float32{float32(i) + offset, 10 * offset, 0})
+	}
+	return keyframe
+}
+
+func BenchmarkGlobalPoseRecursive(b *testing.B) {
+	legacy := newLegacyPose(benchSkeleton())
+	legacy.localPose = benchKeyframe(1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		legacy.calcGlobalPoseMatrices()
+	}
+}
+
+func BenchmarkGlobalPoseLinear(b *testing.B) {
+	animator, err := NewAnimator(benchSkeleton(), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	keyframe := benchKeyframe(1)
+	animator.localPose.setKeyframe(&keyframe)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		animator.CalcGlobalPoseMatrices()
+	}
+}
+
+func BenchmarkLerpKeyframe(b *testing.B) {
+	first, second, result := benchKeyframe(0), benchKeyframe(1), benchKeyframe(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		legacyLerpKeyframe(&first, &second, 0.3, &result)
+	}
+}
+
+// BenchmarkLerpPose gains on BenchmarkLerpKeyframe by the nlerp of the
+// rotations, the layout alone does not make the lerp cheaper
+func BenchmarkLerpPose(b *testing.B) {
+	first, second, result := newPose(benchBoneCount), newPose(benchBoneCount), newPose(benchBoneCount)
+	firstKeyframe, secondKeyframe := benchKeyframe(0), benchKeyframe(1)
+	first.setKeyframe(&firstKeyframe)
+	second.setKeyframe(&secondKeyframe)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lerpPose(&first, &second, 0.3, &result)
+	}
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	first, second := benchKeyframe(0), benchKeyframe(1)
+	second.SampleTime = 1
+	animator, err := NewAnimator(benchSkeleton(), []Animation{{Name: "walk", Duration: 1, Keyframes: []Keyframe{first, second}}, {Name: "run", Duration: 2, Keyframes: []Keyframe{second, {Transforms: first.Transforms, SampleTime: 2}}}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	animator.SetBlendTree(&LerpNode{A: &ClipNode{Animation: 0}, B: &ClipNode{Animation: 1}, Weight: 0.4})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		animator.Update(1.0 / 60)
+	}
+}
@@ -0,0 +1,262 @@
+package anim
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// BlendSpace1D blends the two samples around the value of Parameter, values
+// past either end play the outermost sample
+type BlendSpace1D struct {
+	Parameter string
+	Samples   []BlendSample1D
+}
+
+type BlendSample1D struct {
+	Position float32
+	Node     BlendNode
+}
+
+// BlendSpace2D blends the samples of the triangle that contains the point
+// (ParameterX, ParameterY) by its barycentric coordinates, points outside of
+// the samples are projected onto their closest edge
+type BlendSpace2D struct {
+	ParameterX, ParameterY string
+	Samples                []BlendSample2D
+	triangles              [][3]int
+}
+
+type BlendSample2D struct {
+	Position mgl32.Vec2
+	Node     BlendNode
+}
+
+// NewBlendSpace1D sorts the samples by position
+func NewBlendSpace1D(parameter string, samples []BlendSample1D) (*BlendSpace1D, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("anim: blend space %v has no samples", parameter)
+	}
+	samples = append([]BlendSample1D(nil), samples...)
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Position < samples[j].Position })
+	for i := 1; i < len(samples); i++ {
+		if samples[i].Position == samples[i-1].Position {
+			return nil, fmt.Errorf("anim: blend space %v has two samples at %v", parameter, samples[i].Position)
+		}
+	}
+	return &BlendSpace1D{Parameter: parameter, Samples: samples}, nil
+}
+
+// NewBlendSpace2D triangulates the sample positions, at least three of which
+// must not lie on a line
+func NewBlendSpace2D(parameterX, parameterY string, samples []BlendSample2D) (*BlendSpace2D, error) {
+	positions := make([]mgl32.Vec2, len(samples))
+	for i := range samples {
+		positions[i] = samples[i].Position
+		for j := 0; j < i; j++ {
+			if positions[j] == positions[i] {
+				return nil, fmt.Errorf("anim: blend space (%v, %v) has two samples at %v", parameterX, parameterY, positions[i])
+			}
+		}
+	}
+	triangles := triangulate(positions)
+	if len(triangles) == 0 {
+		return nil, fmt.Errorf("anim: blend space (%v, %v) needs three samples that are not on a line", parameterX, parameterY)
+	}
+	return &BlendSpace2D{ParameterX: parameterX, ParameterY: parameterY, Samples: append([]BlendSample2D(nil), samples...), triangles: triangles}, nil
+}
+
+func (s *BlendSpace1D) evaluate(a *Animator, slot int, weight float32) {
+	indices, weights := s.weights(a.parameters[s.Parameter])
+	nodes := [2]BlendNode{s.Samples[indices[0]].Node, s.Samples[indices[1]].Node}
+	blendWeighted(a, slot, weight, nodes[:], weights[:])
+}
+
+func (s *BlendSpace1D) weights(x float32) ([2]int, [2]float32) {
+	last := len(s.Samples) - 1
+	if x <= s.Samples[0].Position {
+		return [2]int{0, 0}, [2]float32{1, 0}
+	} else if x >= s.Samples[last].Position {
+		return [2]int{last, last}, [2]float32{1, 0}
+	}
+	i := sort.Search(len(s.Samples), func(i int) bool { return s.Samples[i].Position > x }) - 1
+	t := (x - s.Samples[i].Position) / (s.Samples[i+1].Position - s.Samples[i].Position)
+	return [2]int{i, i + 1}, [2]float32{1 - t, t}
+}
+
+func (s *BlendSpace1D) poseCount() int {
+	count := 0
+	for i := range s.Samples {
+		count = maxInt(count, s.Samples[i].Node.poseCount())
+	}
+	return 1 + count
+}
+
+func (s *BlendSpace1D) restart(a *Animator) {
+	for i := range s.Samples {
+		s.Samples[i].Node.restart(a)
+	}
+}
+
+func (s *BlendSpace2D) evaluate(a *Animator, slot int, weight float32) {
+	indices, weights := s.weights(mgl32.Vec2{a.parameters[s.ParameterX], a.parameters[s.ParameterY]})
+	nodes := [3]BlendNode{s.Samples[indices[0]].Node, s.Samples[indices[1]].Node, s.Samples[indices[2]].Node}
+	blendWeighted(a, slot, weight, nodes[:], weights[:])
+}
+
+func (s *BlendSpace2D) weights(p mgl32.Vec2) ([3]int, [3]float32) {
+	best, bestDistance := 0, float32(-1)
+	var bestPoint mgl32.Vec2
+	for i, triangle := range s.triangles {
+		closest := closestPointOnTriangle(p, s.Samples[triangle[0]].Position, s.Samples[triangle[1]].Position, s.Samples[triangle[2]].Position)
+		if distance := closest.Sub(p).Len(); bestDistance < 0 || distance < bestDistance {
+			best, bestDistance, bestPoint = i, distance, closest
+		}
+		if bestDistance == 0 {
+			break
+		}
+	}
+	triangle := s.triangles[best]
+	u, v, w := barycentric(bestPoint, s.Samples[triangle[0]].Position, s.Samples[triangle[1]].Position, s.Samples[triangle[2]].Position)
+	return triangle, [3]float32{u, v, w}
+}
+
+func (s *BlendSpace2D) poseCount() int {
+	count := 0
+	for i := range s.Samples {
+		count = maxInt(count, s.Samples[i].Node.poseCount())
+	}
+	return 1 + count
+}
+
+func (s *BlendSpace2D) restart(a *Animator) {
+	for i := range s.Samples {
+		s.Samples[i].Node.restart(a)
+	}
+}
+
+// blendWeighted blends any number of weighted nodes by lerping each one into
+// the running result by its share of the weight seen so far
+func blendWeighted(a *Animator, slot int, weight float32, nodes []BlendNode, weights []float32) {
+	total := float32(0)
+	for i, node := range nodes {
+		if weights[i] <= 0 {
+			continue
+		}
+		if total == 0 {
+			node.evaluate(a, slot, weight*weights[i])
+		} else {
+			node.evaluate(a, slot+1, weight*weights[i])
+			a.linearBlend(slot, slot+1, weights[i]/(total+weights[i]), slot)
+		}
+		total += weights[i]
+	}
+	if total == 0 {
+		nodes[0].evaluate(a, slot, weight)
+	}
+}
+
+func barycentric(p, a, b, c mgl32.Vec2) (float32, float32, float32) {
+	v0, v1, v2 := b.Sub(a), c.Sub(a), p.Sub(a)
+	denominator := v0[0]*v1[1] - v1[0]*v0[1]
+	v := (v2[0]*v1[1] - v1[0]*v2[1]) / denominator
+	w := (v0[0]*v2[1] - v2[0]*v0[1]) / denominator
+	return 1 - v - w, v, w
+}
+
+func closestPointOnTriangle(p, a, b, c mgl32.Vec2) mgl32.Vec2 {
+	if u, v, w := barycentric(p, a, b, c); u >= 0 && v >= 0 && w >= 0 {
+		return p
+	}
+	closest := closestPointOnSegment(p, a, b)
+	for _, candidate := range [2]mgl32.Vec2{closestPointOnSegment(p, b, c), closestPointOnSegment(p, c, a)} {
+		if candidate.Sub(p).Len() < closest.Sub(p).Len() {
+			closest = candidate
+		}
+	}
+	return closest
+}
+
+func closestPointOnSegment(p, a, b mgl32.Vec2) mgl32.Vec2 {
+	ab := b.Sub(a)
+	t := clamp(0, p.Sub(a).Dot(ab)/ab.Dot(ab), 1)
+	return a.Add(ab.Mul(t))
+}
+
+// triangulate returns the Delaunay triangulation of the points, built with
+// Bowyer-Watson insertion
+func triangulate(points []mgl32.Vec2) [][3]int {
+	if len(points) < 3 {
+		return nil
+	}
+	low, high := points[0], points[0]
+	for _, p := range points {
+		for i := 0; i < 2; i++ {
+			if p[i] < low[i] {
+				low[i] = p[i]
+			} else if p[i] > high[i] {
+				high[i] = p[i]
+			}
+		}
+	}
+	center, size := low.Add(high).Mul(0.5), high.Sub(low).Len()
+	if size == 0 {
+		return nil
+	}
+	//Vertices of a triangle that contains every point go after the points
+	n := len(points)
+	vertices := append(append([]mgl32.Vec2(nil), points...),
+		center.Add(mgl32.Vec2{-100 * size, -100 * size}), center.Add(mgl32.Vec2{100 * size, -100 * size}), center.Add(mgl32.Vec2{0, 100 * size}))
+	triangles := [][3]int{{n, n + 1, n + 2}}
+	for i := 0; i < n; i++ {
+		var kept [][3]int
+		edgeCount := make(map[[2]int]int)
+		var edges [][2]int
+		for _, triangle := range triangles {
+			if !inCircumcircle(vertices[i], vertices[triangle[0]], vertices[triangle[1]], vertices[triangle[2]]) {
+				kept = append(kept, triangle)
+				continue
+			}
+			for e := 0; e < 3; e++ {
+				edge := [2]int{triangle[e], triangle[(e+1)%3]}
+				if edge[0] > edge[1] {
+					edge[0], edge[1] = edge[1], edge[0]
+				}
+				if edgeCount[edge] == 0 {
+					edges = append(edges, edge)
+				}
+				edgeCount[edge]++
+			}
+		}
+		//Edges of a single removed triangle bound the hole left by the point
+		for _, edge := range edges {
+			if edgeCount[edge] == 1 {
+				kept = append(kept, [3]int{edge[0], edge[1], i})
+			}
+		}
+		triangles = kept
+	}
+	var result [][3]int
+	for _, triangle := range triangles {
+		if triangle[0] < n && triangle[1] < n && triangle[2] < n && area(points[triangle[0]], points[triangle[1]], points[triangle[2]]) != 0 {
+			result = append(result, triangle)
+		}
+	}
+	return result
+}
+
+func area(a, b, c mgl32.Vec2) float32 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (c[0]-a[0])*(b[1]-a[1])
+}
+
+func inCircumcircle(p, a, b, c mgl32.Vec2) bool {
+	ax, ay := float64(a[0]-p[0]), float64(a[1]-p[1])
+	bx, by := float64(b[0]-p[0]), float64(b[1]-p[1])
+	cx, cy := float64(c[0]-p[0]), float64(c[1]-p[1])
+	determinant := (ax*ax+ay*ay)*(bx*cy-cx*by) - (bx*bx+by*by)*(ax*cy-cx*ay) + (cx*cx+cy*cy)*(ax*by-bx*ay)
+	if area(a, b, c) < 0 {
+		determinant = -determinant
+	}
+	return determinant > 0
+}
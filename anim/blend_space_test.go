@@ -0,0 +1,86 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBlendSpace1D(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(1), []Animation{testClip("idle", 1, 0), testClip("walk", 1, 2), testClip("run", 1, 6)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	space, err := NewBlendSpace1D("speed", []BlendSample1D{{Position: 1, Node: &ClipNode{Animation: 2}}, {Position: 0, Node: &ClipNode{Animation: 0}}, {Position: 0.5, Node: &ClipNode{Animation: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(space)
+	for _, test := range []struct{ speed, x float32 }{{-1, 0}, {0, 0}, {0.25, 1}, {0.5, 2}, {0.75, 4}, {2, 6}} {
+		animator.SetParameter("speed", test.speed)
+		animator.Update(0.1)
+		if x := animator.GlobalPoseMatrices[0].Col(3).X(); !mgl32.FloatEqual(x, test.x) {
+			t.Errorf("speed %v: expected %v, got %v", test.speed, test.x, x)
+		}
+	}
+	if _, err := NewBlendSpace1D("speed", []BlendSample1D{{Position: 1, Node: space}, {Position: 1, Node: space}}); err == nil {
+		t.Errorf("expected an error for samples at the same position")
+	}
+}
+
+func TestBlendSpace2DWeights(t *testing.T) {
+	//A square of strafing clips around a center idle
+	positions := []mgl32.Vec2{{0, 0}, {1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {-1, 1}, {1, -1}, {-1, -1}}
+	samples := make([]BlendSample2D, len(positions))
+	for i := range samples {
+		samples[i] = BlendSample2D{Position: positions[i], Node: &ClipNode{Animation: i}}
+	}
+	space, err := NewBlendSpace2D("x", "z", samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(space.triangles) != 8 {
+		t.Errorf("expected 8 triangles, got %v", len(space.triangles))
+	}
+	for _, p := range []mgl32.Vec2{{0, 0}, {0.3, 0.6}, {-0.5, -0.25}, {1, -1}, {2, 0.5}, {-3, -3}} {
+		indices, weights := space.weights(p)
+		var blended mgl32.Vec2
+		total := float32(0)
+		for i := range indices {
+			if weights[i] < -1e-6 {
+				t.Errorf("%v: negative weight %v", p, weights)
+			}
+			blended = blended.Add(positions[indices[i]].Mul(weights[i]))
+			total += weights[i]
+		}
+		//Weights reproduce the point, clamped to the square
+		expected := mgl32.Vec2{clamp(-1, p[0], 1), clamp(-1, p[1], 1)}
+		if !mgl32.FloatEqual(total, 1) || !blended.ApproxEqualThreshold(expected, 1e-5) {
+			t.Errorf("%v: expected weights summing to 1 that blend to %v, got %v from %v", p, expected, blended, weights)
+		}
+	}
+	if _, err := NewBlendSpace2D("x", "z", samples[:3]); err == nil {
+		t.Errorf("expected an error for samples on a line")
+	}
+}
+
+func TestBlendSpace2DEvaluate(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(1), []Animation{testClip("a", 1, 0), testClip("b", 1, 3), testClip("c", 1, 6)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := ParseBlendTree([]byte(`{"type": "blend_space_2d", "parameter": "x", "parameter_y": "y", "samples": [
+		{"x": 0, "y": 0, "node": {"type": "clip", "clip": "a"}},
+		{"x": 1, "y": 0, "node": {"type": "clip", "clip": "b"}},
+		{"x": 0, "y": 1, "node": {"type": "clip", "clip": "c"}}]}`), animator.skeleton, animator.animations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(root)
+	animator.SetParameter("x", 0.25)
+	animator.SetParameter("y", 0.5)
+	animator.Update(0.1)
+	if x := animator.GlobalPoseMatrices[0].Col(3).X(); !mgl32.FloatEqual(x, 0.25*3+0.5*6) {
+		t.Errorf("expected %v, got %v", 0.25*3+0.5*6, x)
+	}
+}
@@ -0,0 +1,384 @@
+package anim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// BlendNode is a node of a blend tree evaluated by the Animator. A node writes
+// its pose into the working pose at slot and may use the slots after it as
+// scratch space, weight is how much the node contributes to the final pose.
+// poseCount reports how many slots a node needs and restart rewinds its clips
+// to the animator's current time
+type BlendNode interface {
+	evaluate(a *Animator, slot int, weight float32)
+	poseCount() int
+	restart(a *Animator)
+}
+
+// ClipNode samples an animation, at the value of Parameter when it is set and
+// by the animator's clock otherwise. Clips with the same SyncGroup share the
+// phase of their cycles instead of running on clocks of their own, so a walk
+// and a run blended by speed keep their feet in step, see SetSyncMarkers
+type ClipNode struct {
+	Animation int
+	Parameter string
+	SyncGroup string
+}
+
+// LerpNode blends from A to B by Parameter, or by Weight when it has none
+type LerpNode struct {
+	A, B      BlendNode
+	Parameter string
+	Weight    float32
+}
+
+// AdditiveNode adds the Additive pose on top of Base, scaled by Parameter or
+// Weight
+type AdditiveNode struct {
+	Base, Additive BlendNode
+	Parameter      string
+	Weight         float32
+}
+
+// MaskNode blends from Base to Override by the weight Mask gives each bone
+type MaskNode struct {
+	Base, Override BlendNode
+	Mask           *BoneMask
+}
+
+func (n *ClipNode) evaluate(a *Animator, slot int, weight float32) {
+	if n.Parameter != "" {
+		a.sampleAtParameter(n.Animation, a.parameters[n.Parameter], slot, weight)
+	} else if n.SyncGroup != "" {
+		a.sampleSynced(n.SyncGroup, n.Animation, slot, weight)
+	} else {
+		a.sampleAtGlobalTime(n.Animation, slot, weight)
+	}
+}
+
+func (n *ClipNode) poseCount() int {
+	return 1
+}
+
+// Synced clips keep the group's phase, so entering a state does not break step
+func (n *ClipNode) restart(a *Animator) {
+	if n.SyncGroup != "" {
+		return
+	}
+	state := &a.animationStates[n.Animation]
+	state.time, state.clock, state.clockTime = a.startTime(n.Animation), a.clock, a.clock.time
+}
+
+func (n *LerpNode) evaluate(a *Animator, slot int, weight float32) {
+	t := a.weight(n.Parameter, n.Weight)
+	//Skip the branch that does not contribute
+	if t <= 0 {
+		n.A.evaluate(a, slot, weight)
+		return
+	} else if t >= 1 {
+		n.B.evaluate(a, slot, weight)
+		return
+	}
+	n.A.evaluate(a, slot, weight*(1-t))
+	n.B.evaluate(a, slot+1, weight*t)
+	a.linearBlend(slot, slot+1, t, slot)
+}
+
+func (n *LerpNode) poseCount() int {
+	return maxInt(n.A.poseCount(), 1+n.B.poseCount())
+}
+
+func (n *LerpNode) restart(a *Animator) {
+	n.A.restart(a)
+	n.B.restart(a)
+}
+
+func (n *AdditiveNode) evaluate(a *Animator, slot int, weight float32) {
+	t := a.weight(n.Parameter, n.Weight)
+	n.Base.evaluate(a, slot, weight)
+	if t == 0 {
+		return
+	}
+	n.Additive.evaluate(a, slot+1, weight*t)
+	a.additiveBlend(slot, slot+1, t, slot)
+}
+
+func (n *AdditiveNode) poseCount() int {
+	return maxInt(n.Base.poseCount(), 1+n.Additive.poseCount())
+}
+
+func (n *AdditiveNode) restart(a *Animator) {
+	n.Base.restart(a)
+	n.Additive.restart(a)
+}
+
+func (n *MaskNode) evaluate(a *Animator, slot int, weight float32) {
+	n.Base.evaluate(a, slot, weight)
+	n.Override.evaluate(a, slot+1, weight)
+	blendMasked(&a.workingPoses[slot], &a.workingPoses[slot+1], LayerOverride, n.Mask, 1)
+}
+
+func (n *MaskNode) poseCount() int {
+	return maxInt(n.Base.poseCount(), 1+n.Override.poseCount())
+}
+
+func (n *MaskNode) restart(a *Animator) {
+	n.Base.restart(a)
+	n.Override.restart(a)
+}
+
+func (a *Animator) weight(parameter string, fallback float32) float32 {
+	if parameter == "" {
+		return fallback
+	}
+	return a.parameters[parameter]
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// blendNodeJSON is the data file form of every node type, children and fields
+// that do not apply to a type are left out
+type blendNodeJSON struct {
+	Type      string         `json:"type"`
+	Clip      string         `json:"clip,omitempty"`
+	Animation *int           `json:"animation,omitempty"`
+	Parameter string         `json:"parameter,omitempty"`
+	SyncGroup string         `json:"sync_group,omitempty"`
+	Weight    *float32       `json:"weight,omitempty"`
+	Mask      string         `json:"mask,omitempty"`
+	A         *blendNodeJSON `json:"a,omitempty"`
+	B         *blendNodeJSON `json:"b,omitempty"`
+	Base      *blendNodeJSON `json:"base,omitempty"`
+	Additive  *blendNodeJSON `json:"additive,omitempty"`
+	Override  *blendNodeJSON `json:"override,omitempty"`
+	//Blend spaces, the 2D one also uses ParameterY
+	ParameterY string            `json:"parameter_y,omitempty"`
+	Samples    []blendSampleJSON `json:"samples,omitempty"`
+	//State machines
+	Entry       string           `json:"entry,omitempty"`
+	States      []stateJSON      `json:"states,omitempty"`
+	Transitions []transitionJSON `json:"transitions,omitempty"`
+}
+
+// Position is the sample's location in a 1D blend space, X and Y in a 2D one
+type blendSampleJSON struct {
+	Position float32        `json:"position,omitempty"`
+	X        float32        `json:"x,omitempty"`
+	Y        float32        `json:"y,omitempty"`
+	Node     *blendNodeJSON `json:"node"`
+}
+
+type stateJSON struct {
+	Name string         `json:"name"`
+	Node *blendNodeJSON `json:"node"`
+}
+
+type transitionJSON struct {
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	Conditions    []conditionJSON `json:"conditions,omitempty"`
+	Trigger       string          `json:"trigger,omitempty"`
+	Duration      float32         `json:"duration,omitempty"`
+	Easing        string          `json:"easing,omitempty"`
+	ExitTime      float32         `json:"exit_time,omitempty"`
+	Interruptible bool            `json:"interruptible,omitempty"`
+}
+
+// Comparison is one of ">", "<", "==" and "!="
+type conditionJSON struct {
+	Parameter  string  `json:"parameter"`
+	Comparison string  `json:"comparison"`
+	Value      float32 `json:"value"`
+}
+
+// LoadBlendTree reads a JSON blend tree file and makes it the animator's tree
+func (a *Animator) LoadBlendTree(fileName string) error {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("anim: blend tree read error: %v", err)
+	}
+	root, err := ParseBlendTree(data, a.skeleton, a.animations)
+	if err != nil {
+		return err
+	}
+	a.SetBlendTree(root)
+	return nil
+}
+
+// ParseBlendTree builds a blend tree from JSON, clips are referenced either by
+// animation index or by name and masks by their name in the skeleton
+func ParseBlendTree(data []byte, skeleton *Skeleton, animations []Animation) (BlendNode, error) {
+	var description blendNodeJSON
+	if err := json.Unmarshal(data, &description); err != nil {
+		return nil, fmt.Errorf("anim: blend tree: %v", err)
+	}
+	root, err := description.build(skeleton, animations)
+	if err != nil {
+		return nil, fmt.Errorf("anim: blend tree: %v", err)
+	}
+	return root, nil
+}
+
+func (d *blendNodeJSON) build(skeleton *Skeleton, animations []Animation) (BlendNode, error) {
+	if d == nil {
+		return nil, fmt.Errorf("missing node")
+	}
+	weight := func(fallback float32) float32 {
+		if d.Weight != nil {
+			return *d.Weight
+		}
+		return fallback
+	}
+	switch d.Type {
+	case "clip":
+		index, err := d.animationIndex(animations)
+		if err != nil {
+			return nil, err
+		}
+		return &ClipNode{Animation: index, Parameter: d.Parameter, SyncGroup: d.SyncGroup}, nil
+	case "lerp":
+		first, second, err := buildPair(d.A, d.B, skeleton, animations)
+		if err != nil {
+			return nil, fmt.Errorf("lerp: %v", err)
+		}
+		return &LerpNode{A: first, B: second, Parameter: d.Parameter, Weight: weight(0)}, nil
+	case "additive":
+		base, additive, err := buildPair(d.Base, d.Additive, skeleton, animations)
+		if err != nil {
+			return nil, fmt.Errorf("additive: %v", err)
+		}
+		return &AdditiveNode{Base: base, Additive: additive, Parameter: d.Parameter, Weight: weight(1)}, nil
+	case "mask":
+		base, override, err := buildPair(d.Base, d.Override, skeleton, animations)
+		if err != nil {
+			return nil, fmt.Errorf("mask: %v", err)
+		}
+		mask, present := skeleton.Masks[d.Mask]
+		if !present {
+			return nil, fmt.Errorf("mask: unknown bone mask %q", d.Mask)
+		}
+		return &MaskNode{Base: base, Override: override, Mask: mask}, nil
+	case "blend_space_1d", "blend_space_2d":
+		space, err := d.buildBlendSpace(skeleton, animations)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", d.Type, err)
+		}
+		return space, nil
+	case "state_machine":
+		machine, err := d.buildStateMachine(skeleton, animations)
+		if err != nil {
+			return nil, fmt.Errorf("state machine: %v", err)
+		}
+		return machine, nil
+	}
+	return nil, fmt.Errorf("unknown node type %q", d.Type)
+}
+
+func buildPair(first, second *blendNodeJSON, skeleton *Skeleton, animations []Animation) (BlendNode, BlendNode, error) {
+	a, err := first.build(skeleton, animations)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := second.build(skeleton, animations)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
+
+func (d *blendNodeJSON) animationIndex(animations []Animation) (int, error) {
+	if d.Animation != nil {
+		if *d.Animation < 0 || *d.Animation >= len(animations) {
+			return 0, fmt.Errorf("clip: animation %v out of range", *d.Animation)
+		}
+		return *d.Animation, nil
+	}
+	for i := range animations {
+		if animations[i].Name == d.Clip {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("clip: unknown animation %q", d.Clip)
+}
+
+func (d *blendNodeJSON) buildBlendSpace(skeleton *Skeleton, animations []Animation) (BlendNode, error) {
+	nodes := make([]BlendNode, len(d.Samples))
+	for i := range d.Samples {
+		var err error
+		if nodes[i], err = d.Samples[i].Node.build(skeleton, animations); err != nil {
+			return nil, fmt.Errorf("sample %v: %v", i, err)
+		}
+	}
+	if d.Type == "blend_space_1d" {
+		samples := make([]BlendSample1D, len(nodes))
+		for i := range samples {
+			samples[i] = BlendSample1D{Position: d.Samples[i].Position, Node: nodes[i]}
+		}
+		return NewBlendSpace1D(d.Parameter, samples)
+	}
+	samples := make([]BlendSample2D, len(nodes))
+	for i := range samples {
+		samples[i] = BlendSample2D{Position: mgl32.Vec2{d.Samples[i].X, d.Samples[i].Y}, Node: nodes[i]}
+	}
+	return NewBlendSpace2D(d.Parameter, d.ParameterY, samples)
+}
+
+func (d *blendNodeJSON) buildStateMachine(skeleton *Skeleton, animations []Animation) (*StateMachine, error) {
+	machine := &StateMachine{States: make([]State, len(d.States)), Transitions: make([]Transition, len(d.Transitions))}
+	stateIndex := func(name string) (int, error) {
+		if name == "any" {
+			return AnyState, nil
+		}
+		for i := range d.States {
+			if d.States[i].Name == name {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("unknown state %q", name)
+	}
+	for i, state := range d.States {
+		node, err := state.Node.build(skeleton, animations)
+		if err != nil {
+			return nil, fmt.Errorf("state %v: %v", state.Name, err)
+		}
+		machine.States[i] = State{Name: state.Name, Node: node}
+	}
+	var err error
+	if machine.Entry, err = stateIndex(d.Entry); err != nil || machine.Entry == AnyState {
+		return nil, fmt.Errorf("entry: unknown state %q", d.Entry)
+	}
+	for i, description := range d.Transitions {
+		transition := Transition{Trigger: description.Trigger, Duration: description.Duration, ExitTime: description.ExitTime, Interruptible: description.Interruptible}
+		if transition.From, err = stateIndex(description.From); err != nil {
+			return nil, fmt.Errorf("transition %v: %v", i, err)
+		}
+		if transition.To, err = stateIndex(description.To); err != nil || transition.To == AnyState {
+			return nil, fmt.Errorf("transition %v: unknown state %q", i, description.To)
+		}
+		easings := map[string]Easing{"": EaseLinear, "linear": EaseLinear, "in": EaseIn, "out": EaseOut, "in_out": EaseInOut}
+		easing, present := easings[description.Easing]
+		if !present {
+			return nil, fmt.Errorf("transition %v: unknown easing %q", i, description.Easing)
+		}
+		transition.Easing = easing
+		comparisons := map[string]Comparison{">": Greater, "<": Less, "==": Equal, "!=": NotEqual}
+		for _, condition := range description.Conditions {
+			comparison, present := comparisons[condition.Comparison]
+			if !present {
+				return nil, fmt.Errorf("transition %v: unknown comparison %q", i, condition.Comparison)
+			}
+			transition.Conditions = append(transition.Conditions, Condition{Parameter: condition.Parameter, Comparison: comparison, Value: condition.Value})
+		}
+		machine.Transitions[i] = transition
+	}
+	return machine, nil
+}
@@ -0,0 +1,70 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testSkeleton(boneCount int) *Skeleton {
+	skeleton := &Skeleton{Bones: make([]Bone, boneCount)}
+	for i := range skeleton.Bones {
+		skeleton.Bones[i] = Bone{Name: string(rune('a' + i)), BindPose: mgl32.Ident4(), InverseBindPose: mgl32.Ident4(), ParentIndex: i - 1, Index: i}
+	}
+	return skeleton
+}
+
+// testClip moves every bone along x to the given offset
+func testClip(name string, boneCount int, x float32) Animation {
+	keyframe := Keyframe{Transforms: make([]Transform, boneCount)}
+	for i := range keyframe.Transforms {
+		keyframe.Transforms[i] = TransformFromEuler([3]float32{1, 1, 1}, [3]float32{x, 0, 0}, [3]float32{})
+	}
+	return Animation{Name: name, Duration: 1, Keyframes: []Keyframe{keyframe}}
+}
+
+func TestBlendTreeParameters(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(2), []Animation{testClip("walk", 2, 1), testClip("run", 2, 3), testClip("idle", 2, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	move := &LerpNode{A: &ClipNode{Animation: 0}, B: &ClipNode{Animation: 1}, Parameter: "speed"}
+	root := &LerpNode{A: &ClipNode{Animation: 2}, B: move, Parameter: "ground"}
+	animator.SetBlendTree(&MaskNode{Base: root, Override: &ClipNode{Animation: 2}, Mask: &BoneMask{Weights: []float32{0, 1}}})
+	if len(animator.workingPoses) != 3 {
+		t.Errorf("expected a pool of 3 poses, got %v", len(animator.workingPoses))
+	}
+
+	animator.SetParameter("speed", 0.5)
+	animator.SetParameter("ground", 0.5)
+	animator.Update(0.1)
+	if x := animator.GlobalPoseMatrices[0].Col(3).X(); !mgl32.FloatEqual(x, 1) {
+		t.Errorf("expected the root halfway between idle and move, got %v", x)
+	}
+	//The masked child stays at idle relative to its parent
+	if x := animator.GlobalPoseMatrices[1].Col(3).X(); !mgl32.FloatEqual(x, 1) {
+		t.Errorf("expected the masked bone to keep its parent's offset, got %v", x)
+	}
+}
+
+func TestParseBlendTree(t *testing.T) {
+	animations := []Animation{testClip("walk", 1, 1), testClip("head turn", 1, 2)}
+	root, err := ParseBlendTree([]byte(`{"type": "additive", "weight": 0.5,
+		"base": {"type": "lerp", "parameter": "speed", "a": {"type": "clip", "clip": "walk"}, "b": {"type": "clip", "animation": 1, "sync_group": "move"}},
+		"additive": {"type": "clip", "clip": "head turn", "parameter": "head"}}`), testSkeleton(1), animations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	additive, ok := root.(*AdditiveNode)
+	if !ok || additive.Weight != 0.5 || additive.Base.(*LerpNode).Parameter != "speed" || additive.Additive.(*ClipNode).Animation != 1 || additive.Base.(*LerpNode).B.(*ClipNode).SyncGroup != "move" {
+		t.Errorf("unexpected tree %+v", root)
+	}
+	if root.poseCount() != 2 {
+		t.Errorf("expected the tree to need 2 poses, got %v", root.poseCount())
+	}
+	for _, data := range []string{`{"type": "clip", "clip": "swim"}`, `{"type": "lerp", "a": {"type": "clip", "animation": 0}}`, `{"type": "blend"}`} {
+		if _, err := ParseBlendTree([]byte(data), testSkeleton(1), animations); err == nil {
+			t.Errorf("expected an error for %v", data)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package anim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// ClipFormat selects the on-disk variant of an animation file, both hold the
+// same data and are described in docs/animation_format.md
+type ClipFormat int
+
+const (
+	ClipText ClipFormat = iota
+	ClipBinary
+)
+
+const (
+	clipMagic   = "GEANIM"
+	clipVersion = 1
+)
+
+var numberArray = regexp.MustCompile(`\[[-+.eE0-9,\s]*\]`)
+
+var interpolationNames = []string{InterpolationLinear: "linear", InterpolationStep: "step", InterpolationHermite: "hermite", InterpolationBezier: "bezier"}
+
+type clipFileJSON struct {
+	Version    int             `json:"version"`
+	Animations []animationJSON `json:"animations"`
+}
+
+type animationJSON struct {
+	Name     string      `json:"name"`
+	Duration float32     `json:"duration"`
+	Events   []eventJSON `json:"events,omitempty"`
+	Tracks   []trackJSON `json:"tracks"`
+}
+
+type eventJSON struct {
+	Name string  `json:"name"`
+	Time float32 `json:"time"`
+}
+
+// Bone is the name of the animated bone, channels without keys are omitted
+type trackJSON struct {
+	Bone      string       `json:"bone"`
+	Translate *channelJSON `json:"translate,omitempty"`
+	Rotation  *channelJSON `json:"rotation,omitempty"`
+	Scale     *channelJSON `json:"scale,omitempty"`
+}
+
+type channelJSON struct {
+	Interpolation string    `json:"interpolation,omitempty"`
+	Times         []float32 `json:"times"`
+	Values        []float32 `json:"values"`
+	InTangents    []float32 `json:"in_tangents,omitempty"`
+	OutTangents   []float32 `json:"out_tangents,omitempty"`
+}
+
+// SaveAnimations writes the animations of a skeleton to a file, bones are
+// stored by name so the file can be loaded for any skeleton that has them
+func SaveAnimations(fileName string, skeleton *Skeleton, animations []Animation, format ClipFormat) error {
+	data, err := MarshalAnimations(skeleton, animations, format)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
+		return fmt.Errorf("anim: animation write error: %v", err)
+	}
+	return nil
+}
+
+// LoadAnimations reads an animation file of either format for the skeleton
+func LoadAnimations(fileName string, skeleton *Skeleton) ([]Animation, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("anim: animation read error: %v", err)
+	}
+	animations, err := ParseAnimations(data, skeleton)
+	if err != nil {
+		return nil, fmt.Errorf("%v in %v", err, fileName)
+	}
+	return animations, nil
+}
+
+// MarshalAnimations encodes the animations, clips that only have dense
+// keyframes are converted into tracks first
+func MarshalAnimations(skeleton *Skeleton, animations []Animation, format ClipFormat) ([]byte, error) {
+	file := clipFileJSON{Version: clipVersion, Animations: make([]animationJSON, len(animations))}
+	for i := range animations {
+		animation := animations[i]
+		if len(animation.Tracks) == 0 {
+			animation.BuildTracks()
+		}
+		if err := animation.validateTracks(len(skeleton.Bones)); err != nil {
+			return nil, fmt.Errorf("anim: animation %v: %v", animation.Name, err)
+		}
+		description := &file.Animations[i]
+		description.Name, description.Duration = animation.Name, animation.Duration
+		for _, event := range animation.Events {
+			description.Events = append(description.Events, eventJSON{Name: event.Name, Time: event.Time})
+		}
+		description.Tracks = make([]trackJSON, len(animation.Tracks))
+		for j, track := range animation.Tracks {
+			description.Tracks[j] = trackJSON{Bone: skeleton.Bones[track.BoneIndex].Name,
+				Translate: channelToJSON(&track.Translate), Rotation: channelToJSON(&track.Rotation), Scale: channelToJSON(&track.Scale)}
+		}
+	}
+	switch format {
+	case ClipText:
+		data, err := json.MarshalIndent(file, "", "\t")
+		if err != nil {
+			return nil, fmt.Errorf("anim: animation encoding error: %v", err)
+		}
+		//Keep arrays of numbers on one line
+		return numberArray.ReplaceAllFunc(data, func(array []byte) []byte {
+			return bytes.Replace(bytes.Join(bytes.Fields(array), nil), []byte(","), []byte(", "), -1)
+		}), nil
+	case ClipBinary:
+		return marshalClipBinary(&file), nil
+	}
+	return nil, fmt.Errorf("anim: unknown clip format %v", format)
+}
+
+// ParseAnimations decodes animations of either format, the binary one is told
+// apart by its magic header
+func ParseAnimations(data []byte, skeleton *Skeleton) ([]Animation, error) {
+	var file clipFileJSON
+	if bytes.HasPrefix(data, []byte(clipMagic)) {
+		if err := parseClipBinary(data, &file); err != nil {
+			return nil, fmt.Errorf("anim: animation file: %v", err)
+		}
+	} else if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("anim: animation file: %v", err)
+	}
+	if file.Version != clipVersion {
+		return nil, fmt.Errorf("anim: animation file: unsupported version %v", file.Version)
+	}
+	animations := make([]Animation, len(file.Animations))
+	for i, description := range file.Animations {
+		animation := Animation{Name: description.Name, Duration: description.Duration, Tracks: make([]Track, len(description.Tracks))}
+		for _, event := range description.Events {
+			animation.Events = append(animation.Events, Event{Name: event.Name, Time: event.Time})
+		}
+		for j, track := range description.Tracks {
+			bone := skeleton.BoneIndex(track.Bone)
+			if bone < 0 {
+				return nil, fmt.Errorf("anim: animation %v: unknown bone %v", description.Name, track.Bone)
+			}
+			animation.Tracks[j].BoneIndex = bone
+			for _, channel := range []struct {
+				description *channelJSON
+				result      *Channel
+			}{{track.Translate, &animation.Tracks[j].Translate}, {track.Rotation, &animation.Tracks[j].Rotation}, {track.Scale, &animation.Tracks[j].Scale}} {
+				if err := channel.description.build(channel.result); err != nil {
+					return nil, fmt.Errorf("anim: animation %v, bone %v: %v", description.Name, track.Bone, err)
+				}
+			}
+		}
+		if err := animation.validateTracks(len(skeleton.Bones)); err != nil {
+			return nil, fmt.Errorf("anim: animation %v: %v", description.Name, err)
+		}
+		animations[i] = animation
+	}
+	return animations, nil
+}
+
+func channelToJSON(c *Channel) *channelJSON {
+	if len(c.Times) == 0 {
+		return nil
+	}
+	return &channelJSON{Interpolation: interpolationNames[c.Interpolation], Times: c.Times, Values: c.Values, InTangents: c.InTangents, OutTangents: c.OutTangents}
+}
+
+func (description *channelJSON) build(c *Channel) error {
+	if description == nil {
+		return nil
+	}
+	interpolation, ok := parseInterpolation(description.Interpolation)
+	if !ok {
+		return fmt.Errorf("unknown interpolation %q", description.Interpolation)
+	}
+	*c = Channel{Times: description.Times, Values: description.Values, Interpolation: interpolation, InTangents: description.InTangents, OutTangents: description.OutTangents}
+	return nil
+}
+
+// parseInterpolation accepts the names in interpolationNames, an empty name is
+// linear
+func parseInterpolation(name string) (Interpolation, bool) {
+	if name == "" {
+		return InterpolationLinear, true
+	}
+	for i := range interpolationNames {
+		if strings.EqualFold(name, interpolationNames[i]) {
+			return Interpolation(i), true
+		}
+	}
+	return InterpolationLinear, false
+}
+
+// The binary format stores the same structure little endian, strings and
+// arrays are prefixed by their uint32 length
+func marshalClipBinary(file *clipFileJSON) []byte {
+	var b bytes.Buffer
+	b.WriteString(clipMagic)
+	writeUint32(&b, uint32(file.Version))
+	writeUint32(&b, uint32(len(file.Animations)))
+	for _, animation := range file.Animations {
+		writeString(&b, animation.Name)
+		writeFloats(&b, []float32{animation.Duration})
+		writeUint32(&b, uint32(len(animation.Events)))
+		for _, event := range animation.Events {
+			writeString(&b, event.Name)
+			writeFloats(&b, []float32{event.Time})
+		}
+		writeUint32(&b, uint32(len(animation.Tracks)))
+		for _, track := range animation.Tracks {
+			writeString(&b, track.Bone)
+			for _, channel := range [3]*channelJSON{track.Translate, track.Rotation, track.Scale} {
+				if channel == nil {
+					channel = &channelJSON{}
+				}
+				interpolation, _ := parseInterpolation(channel.Interpolation)
+				b.WriteByte(byte(interpolation))
+				for _, values := range [4][]float32{channel.Times, channel.Values, channel.InTangents, channel.OutTangents} {
+					writeUint32(&b, uint32(len(values)))
+					writeFloats(&b, values)
+				}
+			}
+		}
+	}
+	return b.Bytes()
+}
+
+func writeUint32(b *bytes.Buffer, value uint32) {
+	binary.Write(b, binary.LittleEndian, value)
+}
+
+func writeFloats(b *bytes.Buffer, values []float32) {
+	binary.Write(b, binary.LittleEndian, values)
+}
+
+func writeString(b *bytes.Buffer, s string) {
+	writeUint32(b, uint32(len(s)))
+	b.WriteString(s)
+}
+
+// clipReader keeps the first error so a whole record can be read before
+// checking it
+type clipReader struct {
+	data []byte
+	err  error
+}
+
+func parseClipBinary(data []byte, file *clipFileJSON) error {
+	r := clipReader{data: data[len(clipMagic):]}
+	file.Version = int(r.uint32())
+	if file.Version != clipVersion {
+		return nil
+	}
+	file.Animations = make([]animationJSON, r.count(1))
+	for i := range file.Animations {
+		animation := &file.Animations[i]
+		animation.Name = r.string()
+		animation.Duration = r.float32()
+		animation.Events = make([]eventJSON, r.count(8))
+		for j := range animation.Events {
+			animation.Events[j] = eventJSON{Name: r.string(), Time: r.float32()}
+		}
+		animation.Tracks = make([]trackJSON, r.count(4))
+		for j := range animation.Tracks {
+			track := &animation.Tracks[j]
+			track.Bone = r.string()
+			for _, channel := range [3]**channelJSON{&track.Translate, &track.Rotation, &track.Scale} {
+				interpolation := int(r.byte())
+				if r.err == nil && interpolation >= len(interpolationNames) {
+					r.err = fmt.Errorf("unknown interpolation %v", interpolation)
+				}
+				description := &channelJSON{}
+				for _, values := range [4]*[]float32{&description.Times, &description.Values, &description.InTangents, &description.OutTangents} {
+					*values = r.floats(r.count(4))
+				}
+				if r.err != nil {
+					return r.err
+				}
+				if len(description.Times) > 0 {
+					description.Interpolation = interpolationNames[interpolation]
+					*channel = description
+				}
+			}
+		}
+	}
+	if r.err == nil && len(r.data) != 0 {
+		r.err = fmt.Errorf("%v unexpected bytes at the end", len(r.data))
+	}
+	return r.err
+}
+
+func (r *clipReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > len(r.data) {
+		r.err = fmt.Errorf("unexpected end of data")
+		return nil
+	}
+	result := r.data[:n]
+	r.data = r.data[n:]
+	return result
+}
+
+func (r *clipReader) byte() byte {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *clipReader) uint32() uint32 {
+	if b := r.take(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+// count reads an element count and checks the data left can hold it, given
+// the smallest size of an element
+func (r *clipReader) count(size int) int {
+	count := int(r.uint32())
+	if r.err == nil && count > len(r.data)/size {
+		r.err = fmt.Errorf("count %v exceeds the data left", count)
+	}
+	if r.err != nil {
+		return 0
+	}
+	return count
+}
+
+func (r *clipReader) float32() float32 {
+	values := r.floats(1)
+	if values == nil {
+		return 0
+	}
+	return values[0]
+}
+
+func (r *clipReader) floats(n int) []float32 {
+	b := r.take(4 * n)
+	if b == nil || n == 0 {
+		return nil
+	}
+	values := make([]float32, n)
+	binary.Read(bytes.NewReader(b), binary.LittleEndian, values)
+	return values
+}
+
+func (r *clipReader) string() string {
+	return string(r.take(r.count(1)))
+}
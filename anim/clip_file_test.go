@@ -0,0 +1,64 @@
+package anim
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnimationFileRoundTrip(t *testing.T) {
+	skeleton := testSkeleton(3)
+	times := []float32{0, 0.5, 1}
+	transforms := []Transform{
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0, 0}, [3]float32{0, 0, 0}),
+		TransformFromEuler([3]float32{1, 2, 1}, [3]float32{1, 0, 0}, [3]float32{0, 45, 0}),
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{2, 1, 0}, [3]float32{0, 90, 0}),
+	}
+	walk := Animation{Name: "walk", Duration: 1, Events: []Event{{Name: "left", Time: 0.25}}, Tracks: []Track{
+		NewCurveTrack(2, times, transforms, InterpolationHermite, nil),
+		NewCurveTrack(0, times, transforms, InterpolationStep, nil),
+	}}
+	clips := []Animation{walk, testClip("idle", 3, 4)}
+	for _, format := range []ClipFormat{ClipText, ClipBinary} {
+		data, err := MarshalAnimations(skeleton, clips, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := ParseAnimations(data, skeleton)
+		if err != nil {
+			t.Fatalf("format %v: %v", format, err)
+		}
+		if !reflect.DeepEqual(loaded[0], walk) {
+			t.Errorf("format %v: expected %+v, got %+v", format, walk, loaded[0])
+		}
+		//Dense keyframes are stored as tracks
+		idle := clips[1]
+		idle.BuildTracks()
+		if loaded[1].Name != "idle" || loaded[1].Keyframes != nil || !reflect.DeepEqual(loaded[1].Tracks, idle.Tracks) {
+			t.Errorf("format %v: expected the idle tracks %+v, got %+v", format, idle.Tracks, loaded[1])
+		}
+	}
+}
+
+func TestAnimationFileErrors(t *testing.T) {
+	skeleton := testSkeleton(2)
+	data, err := MarshalAnimations(skeleton, []Animation{testClip("walk", 2, 1)}, ClipBinary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseAnimations(data[:len(data)-3], skeleton); err == nil || !strings.Contains(err.Error(), "end of data") {
+		t.Errorf("expected an error for truncated data, got %v", err)
+	}
+	if _, err := ParseAnimations(data, testSkeleton(1)); err == nil || !strings.Contains(err.Error(), "unknown bone b") {
+		t.Errorf("expected an error for a missing bone, got %v", err)
+	}
+	for _, text := range []string{
+		`{"version": 2, "animations": []}`,
+		`{"version": 1, "animations": [{"name": "a", "duration": 1, "tracks": [{"bone": "a", "translate": {"interpolation": "cubic", "times": [0], "values": [0, 0, 0]}}]}]}`,
+		`{"version": 1, "animations": [{"name": "a", "duration": 1, "tracks": [{"bone": "a", "translate": {"times": [0, 1], "values": [0, 0, 0]}}]}]}`,
+	} {
+		if _, err := ParseAnimations([]byte(text), skeleton); err == nil {
+			t.Errorf("expected an error for %v", text)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package anim
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Crowd updates many animators in parallel. Animators only write to their own
+// state, so the result does not depend on how they are split between workers.
+//
+// Intervals lowers the update rate of far away or hidden characters, an
+// animator with interval n is updated every nth frame with the time of all n
+// frames. Animators with the same interval are spread over the frames by their
+// index so they do not all update at once.
+//
+// Blend trees and rigs may be shared by the animators, post processors keep
+// scratch state and need one per animator
+type Crowd struct {
+	Animators []*Animator
+	Intervals []int
+	//Goroutines used by Update, GOMAXPROCS when 0
+	Workers int
+	frame   int
+	pending []float32
+	updated []bool
+}
+
+// NewCrowd updates the animators every frame until Intervals is changed
+func NewCrowd(animators []*Animator) *Crowd {
+	c := &Crowd{Animators: animators, Intervals: make([]int, len(animators)), pending: make([]float32, len(animators)), updated: make([]bool, len(animators))}
+	for i := range c.Intervals {
+		c.Intervals[i] = 1
+	}
+	return c
+}
+
+// Add appends an animator updated every frame and returns its index
+func (c *Crowd) Add(animator *Animator) int {
+	c.Animators = append(c.Animators, animator)
+	c.Intervals = append(c.Intervals, 1)
+	c.pending = append(c.pending, 0)
+	c.updated = append(c.updated, false)
+	return len(c.Animators) - 1
+}
+
+// Update advances every animator that is due this frame
+func (c *Crowd) Update(deltaTime float32) {
+	c.frame++
+	workers := c.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(c.Animators) {
+		workers = len(c.Animators)
+	}
+	var wait sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wait.Add(1)
+		go func(start, end int) {
+			defer wait.Done()
+			for i := start; i < end; i++ {
+				c.update(i, deltaTime)
+			}
+		}(w*len(c.Animators)/workers, (w+1)*len(c.Animators)/workers)
+	}
+	wait.Wait()
+}
+
+func (c *Crowd) update(i int, deltaTime float32) {
+	c.pending[i] += deltaTime
+	c.updated[i] = false
+	if interval := c.Intervals[i]; interval > 1 && (c.frame+i)%interval != 0 {
+		return
+	}
+	c.Animators[i].Update(c.pending[i])
+	c.pending[i] = 0
+	c.updated[i] = true
+}
+
+// Updated tells whether the animator's pose changed in the last Update, so
+// its bone palette only needs uploading then
+func (c *Crowd) Updated(i int) bool {
+	return c.updated[i]
+}
+
+// IntervalForDistance picks an update interval that doubles every time the
+// distance doubles past near, up to maxInterval
+func IntervalForDistance(distance, near float32, maxInterval int) int {
+	interval := 1
+	for limit := near; distance > limit && interval < maxInterval; limit *= 2 {
+		interval *= 2
+	}
+	if interval > maxInterval {
+		return maxInterval
+	}
+	return interval
+}
@@ -0,0 +1,87 @@
+package anim
+
+import (
+	"fmt"
+	"testing"
+)
+
+func crowdAnimators(t *testing.T, count int) []*Animator {
+	walk, run := testClip("walk", 3, 1), testClip("run", 3, 3)
+	run.Keyframes = append(run.Keyframes, Keyframe{Transforms: testClip("", 3, 5).Keyframes[0].Transforms, SampleTime: 0.7})
+	walk.Events = []Event{{Name: "step", Time: 0.5}}
+	rig, err := NewRig(testSkeleton(3), []Animation{walk, run, testClip("jump", 3, 8)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//One tree shared by the whole crowd
+	space, err := NewBlendSpace1D("speed", []BlendSample1D{{Position: 0, Node: &ClipNode{Animation: 0}}, {Position: 1, Node: &ClipNode{Animation: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	machine := &StateMachine{
+		States:      []State{{Name: "ground", Node: space}, {Name: "air", Node: &ClipNode{Animation: 2}}},
+		Transitions: []Transition{{From: 0, To: 1, Trigger: "jump", Duration: 0.3}, {From: 1, To: 0, Trigger: "land", Duration: 0.3}},
+	}
+	animators := make([]*Animator, count)
+	for i := range animators {
+		animators[i] = rig.NewAnimator()
+		animators[i].SetBlendTree(machine)
+		animators[i].SetParameter("speed", float32(i%7)/6)
+		animators[i].SetPlaybackRate(0, 1+float32(i%3)/4)
+	}
+	return animators
+}
+
+func TestCrowdMatchesSerialUpdate(t *testing.T) {
+	serial, parallel := crowdAnimators(t, 100), crowdAnimators(t, 100)
+	crowd := NewCrowd(parallel)
+	crowd.Workers = 8
+	for frame := 0; frame < 40; frame++ {
+		for i := range serial {
+			if frame == 10+i%5 {
+				serial[i].Trigger("jump")
+				parallel[i].Trigger("jump")
+			}
+			serial[i].Update(1.0 / 30)
+		}
+		crowd.Update(1.0 / 30)
+		for i := range serial {
+			if fmt.Sprint(serial[i].GlobalPoseMatrices, serial[i].Events()) != fmt.Sprint(parallel[i].GlobalPoseMatrices, parallel[i].Events()) {
+				t.Fatalf("frame %v, animator %v: expected %v, got %v", frame, i, serial[i].GlobalPoseMatrices, parallel[i].GlobalPoseMatrices)
+			}
+		}
+	}
+}
+
+func TestCrowdIntervals(t *testing.T) {
+	animators := crowdAnimators(t, 3)
+	crowd := NewCrowd(animators)
+	crowd.Intervals[1], crowd.Intervals[2] = 4, 4
+	updates := make([]int, len(animators))
+	for frame := 0; frame < 8; frame++ {
+		crowd.Update(0.25)
+		for i := range animators {
+			if crowd.Updated(i) {
+				updates[i]++
+			}
+		}
+		//Animators with the same interval update on different frames
+		if crowd.Updated(1) && crowd.Updated(2) {
+			t.Errorf("frame %v: expected staggered updates", frame)
+		}
+	}
+	if updates[0] != 8 || updates[1] != 2 || updates[2] != 2 {
+		t.Errorf("expected 8, 2 and 2 updates, got %v", updates)
+	}
+	//Skipped frames are made up for, the clock stays in step
+	for i := range animators {
+		if clock := animators[i].baseClock.time + crowd.pending[i]; clock != 2 {
+			t.Errorf("animator %v: expected 2 seconds, got %v", i, clock)
+		}
+	}
+	for distance, expected := range map[float32]int{1: 1, 10: 1, 15: 2, 30: 4, 1000: 8} {
+		if interval := IntervalForDistance(distance, 10, 8); interval != expected {
+			t.Errorf("distance %v: expected interval %v, got %v", distance, expected, interval)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package anim
+
+import (
+	"fmt"
+	"math"
+)
+
+// Interpolation selects how a Channel is evaluated between two keys
+type Interpolation int
+
+const (
+	InterpolationLinear Interpolation = iota
+	InterpolationStep
+	//Cubic Hermite using the channel's tangents, Catmull-Rom when it has none
+	InterpolationHermite
+	//Cubic Bezier through the (time, value) control points of the tangents
+	InterpolationBezier
+)
+
+func (c *Channel) validateTangents(width int) error {
+	switch c.Interpolation {
+	case InterpolationLinear, InterpolationStep:
+		return nil
+	case InterpolationHermite:
+	case InterpolationBezier:
+		width *= 2
+	default:
+		return fmt.Errorf("unknown interpolation %v", c.Interpolation)
+	}
+	if c.InTangents == nil && c.OutTangents == nil {
+		return nil
+	}
+	if len(c.InTangents) != width*len(c.Times) || len(c.OutTangents) != width*len(c.Times) {
+		return fmt.Errorf("expected %v in and out tangents, got %v and %v", width*len(c.Times), len(c.InTangents), len(c.OutTangents))
+	}
+	return nil
+}
+
+// interpolate writes the value between key k and k+1, alpha is the linear
+// fraction and t the absolute sample time
+func (c *Channel) interpolate(k int, alpha, t float32, width int, result []float32) {
+	if alpha == 0 || c.Interpolation == InterpolationStep {
+		copy(result, c.Values[width*k:width*(k+1)])
+		return
+	}
+	switch {
+	case c.Interpolation == InterpolationBezier && c.OutTangents != nil:
+		c.bezier(k, t, width, result)
+	case c.Interpolation == InterpolationHermite || c.Interpolation == InterpolationBezier:
+		c.hermite(k, alpha, width, result)
+	default:
+		first := c.Values[width*k : width*(k+1)]
+		second := c.Values[width*(k+1) : width*(k+2)]
+		for i := 0; i < width; i++ {
+			result[i] = first[i]*(1-alpha) + second[i]*alpha
+		}
+	}
+}
+
+func (c *Channel) hermite(k int, s float32, width int, result []float32) {
+	dt := c.Times[k+1] - c.Times[k]
+	s2, s3 := s*s, s*s*s
+	h00, h10 := 2*s3-3*s2+1, s3-2*s2+s
+	h01, h11 := -2*s3+3*s2, s3-s2
+	for i := 0; i < width; i++ {
+		p0, p1 := c.Values[width*k+i], c.Values[width*(k+1)+i]
+		m0, m1 := c.slope(k, i, width, c.OutTangents), c.slope(k+1, i, width, c.InTangents)
+		result[i] = h00*p0 + h10*dt*m0 + h01*p1 + h11*dt*m1
+	}
+}
+
+// slope of value i at key k, Catmull-Rom from the neighbouring keys when no
+// tangents were authored
+func (c *Channel) slope(k, i, width int, tangents []float32) float32 {
+	if tangents != nil && c.Interpolation == InterpolationHermite {
+		return tangents[width*k+i]
+	}
+	previous, next := k-1, k+1
+	if previous < 0 {
+		previous = 0
+	}
+	if next > len(c.Times)-1 {
+		next = len(c.Times) - 1
+	}
+	return (c.Values[width*next+i] - c.Values[width*previous+i]) / (c.Times[next] - c.Times[previous])
+}
+
+func (c *Channel) bezier(k int, t float32, width int, result []float32) {
+	t0, t1 := c.Times[k], c.Times[k+1]
+	for i := 0; i < width; i++ {
+		out := 2 * (width*k + i)
+		in := 2 * (width*(k+1) + i)
+		//Control times outside of the key interval would make the curve non monotonic in time
+		ta := clamp(t0, c.OutTangents[out], t1)
+		tb := clamp(t0, c.InTangents[in], t1)
+		s := solveBezier(t0, ta, tb, t1, t)
+		result[i] = cubicBezier(c.Values[width*k+i], c.OutTangents[out+1], c.InTangents[in+1], c.Values[width*(k+1)+i], s)
+	}
+}
+
+func cubicBezier(p0, p1, p2, p3, s float32) float32 {
+	r := 1 - s
+	return r*r*r*p0 + 3*r*r*s*p1 + 3*r*s*s*p2 + s*s*s*p3
+}
+
+// solveBezier finds the curve parameter at which the time curve reaches t,
+// using newton steps guarded by bisection
+func solveBezier(t0, ta, tb, t1, t float32) float32 {
+	low, high := float32(0), float32(1)
+	s := (t - t0) / (t1 - t0)
+	for i := 0; i < 20; i++ {
+		x := cubicBezier(t0, ta, tb, t1, s) - t
+		if float32(math.Abs(float64(x))) < 1e-6 {
+			break
+		}
+		if x > 0 {
+			high = s
+		} else {
+			low = s
+		}
+		r := 1 - s
+		derivative := 3*r*r*(ta-t0) + 6*r*s*(tb-ta) + 3*s*s*(t1-tb)
+		next := s - x/derivative
+		if derivative == 0 || next <= low || next >= high {
+			next = (low + high) / 2
+		}
+		s = next
+	}
+	return s
+}
+
+func clamp(low, value, high float32) float32 {
+	if value < low {
+		return low
+	} else if value > high {
+		return high
+	}
+	return value
+}
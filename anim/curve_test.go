@@ -0,0 +1,74 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestChannelInterpolationModes(t *testing.T) {
+	times := []float32{0, 1, 2}
+	values := []float32{0, 1, 2}
+	channels := map[string]Channel{
+		"step":        {Times: times, Values: values, Interpolation: InterpolationStep},
+		"catmull-rom": {Times: times, Values: values, Interpolation: InterpolationHermite},
+		"hermite": {Times: times, Values: values, Interpolation: InterpolationHermite,
+			InTangents: []float32{1, 1, 1}, OutTangents: []float32{1, 1, 1}},
+		//Control points on thirds of a straight line trace the line itself
+		"bezier": {Times: times, Values: values, Interpolation: InterpolationBezier,
+			InTangents: []float32{-1.0 / 3, -1.0 / 3, 2.0 / 3, 2.0 / 3, 5.0 / 3, 5.0 / 3}, OutTangents: []float32{1.0 / 3, 1.0 / 3, 4.0 / 3, 4.0 / 3, 7.0 / 3, 7.0 / 3}},
+	}
+	for name, channel := range channels {
+		if err := channel.validateTangents(1); err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		for _, sampleTime := range []float32{0, 0.25, 0.5, 1.3, 2} {
+			expected := sampleTime
+			if name == "step" {
+				expected = float32(int(sampleTime))
+			}
+			var result [1]float32
+			channel.sample(sampleTime, 1, result[:])
+			if !mgl32.FloatEqualThreshold(result[0], expected, 1e-4) {
+				t.Errorf("%v at t=%v: expected %v, got %v", name, sampleTime, expected, result[0])
+			}
+		}
+	}
+}
+
+func TestBezierEasing(t *testing.T) {
+	//Flat tangents ease in and out of both keys
+	channel := Channel{Times: []float32{0, 1}, Values: []float32{0, 1}, Interpolation: InterpolationBezier,
+		InTangents: []float32{0, 0, 0.6, 1}, OutTangents: []float32{0.4, 0, 1, 1}}
+	var early, middle, late [1]float32
+	channel.sample(0.1, 1, early[:])
+	channel.sample(0.5, 1, middle[:])
+	channel.sample(0.9, 1, late[:])
+	if !mgl32.FloatEqualThreshold(middle[0], 0.5, 1e-4) {
+		t.Errorf("expected a symmetric curve to pass 0.5 halfway, got %v", middle[0])
+	}
+	if early[0] >= 0.1 || late[0] <= 0.9 || !mgl32.FloatEqualThreshold(early[0]+late[0], 1, 1e-4) {
+		t.Errorf("expected ease in and out, got %v and %v", early[0], late[0])
+	}
+	if err := (&Channel{Times: []float32{0, 1}, Values: []float32{0, 1}, Interpolation: InterpolationBezier, InTangents: []float32{0, 0}, OutTangents: []float32{0, 0}}).validateTangents(1); err == nil {
+		t.Errorf("expected an error for missing control point times")
+	}
+}
+
+func TestCurveTrackRotation(t *testing.T) {
+	transforms := []Transform{
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 90, 0}),
+	}
+	track := NewCurveTrack(0, []float32{0, 1}, transforms, InterpolationStep, nil)
+	var result Transform
+	track.sample(0.99, &result)
+	if !transformsEqual(result, transforms[0]) {
+		t.Errorf("expected step interpolation to hold the first key, got %v", result)
+	}
+	track = NewCurveTrack(0, []float32{0, 1}, transforms, InterpolationHermite, nil)
+	track.sample(0.5, &result)
+	if expected := TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 45, 0}); !transformsEqual(result, expected) {
+		t.Errorf("expected a normalized halfway rotation %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,39 @@
+package anim
+
+// The pose pool methods below were the Animator's public API before blend
+// trees, they are kept for one release so callers can migrate
+
+// SampleLinear samples an animation at time t into a working pose, without
+// firing its events
+//
+// Deprecated: use a ClipNode with a Parameter in the blend tree passed to
+// SetBlendTree
+func (a *Animator) SampleLinear(sampleIndex int, t float32, resultIndex int) {
+	a.reservePoses(resultIndex + 1)
+	a.animations[sampleIndex].sample(t, &a.workingPoses[resultIndex])
+}
+
+// LinearBlend interpolates two working poses into a third
+//
+// Deprecated: use a LerpNode in the blend tree passed to SetBlendTree
+func (a *Animator) LinearBlend(firstIndex, secondIndex int, t float32, resultIndex int) {
+	a.reservePoses(maxInt(maxInt(firstIndex, secondIndex), resultIndex) + 1)
+	a.linearBlend(firstIndex, secondIndex, t, resultIndex)
+}
+
+// AdditiveBlend adds a working pose on top of another, scaled by t
+//
+// Deprecated: use an AdditiveNode in the blend tree passed to SetBlendTree
+func (a *Animator) AdditiveBlend(baseIndex, additiveIndex int, t float32, resultIndex int) {
+	a.reservePoses(maxInt(maxInt(baseIndex, additiveIndex), resultIndex) + 1)
+	a.additiveBlend(baseIndex, additiveIndex, t, resultIndex)
+}
+
+// SampleAtGlobalTime samples an animation by its own clock into a working
+// pose
+//
+// Deprecated: use a ClipNode in the blend tree passed to SetBlendTree
+func (a *Animator) SampleAtGlobalTime(sampleIndex, resultIndex int) {
+	a.reservePoses(resultIndex + 1)
+	a.sampleAtGlobalTime(sampleIndex, resultIndex, 1)
+}
@@ -0,0 +1,55 @@
+package anim
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// DualQuat is a rigid transform, Real holds the rotation and Dual half the
+// translation multiplied by it. The eight floats are laid out Real.W, Real.V,
+// Dual.W, Dual.V so a slice of them uploads as a mat2x4 uniform array
+type DualQuat struct {
+	Real mgl32.Quat
+	Dual mgl32.Quat
+}
+
+// DualQuatFromMat4 keeps the rotation and translation of an affine matrix,
+// scale and shear can not be represented and are dropped
+func DualQuatFromMat4(m mgl32.Mat4) DualQuat {
+	rotation := Mat4ToTransform(m).Rotation
+	translation := mgl32.Quat{V: mgl32.Vec3{m[12], m[13], m[14]}}
+	return DualQuat{Real: rotation, Dual: translation.Mul(rotation).Scale(0.5)}
+}
+
+func (d DualQuat) Mat4() mgl32.Mat4 {
+	translation := d.Dual.Mul(d.Real.Conjugate()).Scale(2).V
+	m := d.Real.Mat4()
+	m[12], m[13], m[14] = translation[0], translation[1], translation[2]
+	return m
+}
+
+// BlendDualQuats blends the weighted dual quaternions linearly and normalizes
+// the result, each one is flipped into the hemisphere of the first so the
+// blend takes the short way around
+func BlendDualQuats(dualQuats []DualQuat, weights []float32) DualQuat {
+	var result DualQuat
+	for i := range dualQuats {
+		w := weights[i]
+		if dualQuats[i].Real.Dot(dualQuats[0].Real) < 0 {
+			w = -w
+		}
+		result.Real = result.Real.Add(dualQuats[i].Real.Scale(w))
+		result.Dual = result.Dual.Add(dualQuats[i].Dual.Scale(w))
+	}
+	length := result.Real.Len()
+	if length == 0 {
+		return DualQuat{Real: mgl32.QuatIdent()}
+	}
+	result.Real, result.Dual = result.Real.Scale(1/length), result.Dual.Scale(1/length)
+	return result
+}
+
+// CalcGlobalDualQuaternions converts the global pose matrices into the dual
+// quaternions used by dual quaternion skinning
+func (a *Animator) CalcGlobalDualQuaternions() {
+	for i := range a.GlobalPoseMatrices {
+		a.GlobalDualQuaternions[i] = DualQuatFromMat4(a.GlobalPoseMatrices[i])
+	}
+}
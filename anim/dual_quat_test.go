@@ -0,0 +1,50 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestDualQuatRoundTrip(t *testing.T) {
+	m := mgl32.Translate3D(1, -2, 0.5).Mul4(mgl32.HomogRotate3D(mgl32.DegToRad(130), mgl32.Vec3{1, 2, -1}.Normalize()))
+	if result := DualQuatFromMat4(m).Mat4(); !result.ApproxEqualThreshold(m, 1e-5) {
+		t.Errorf("expected %v, got %v", m, result)
+	}
+	//Scale is dropped
+	if result := DualQuatFromMat4(m.Mul4(mgl32.Scale3D(2, 2, 2))).Mat4(); !result.ApproxEqualThreshold(m, 1e-5) {
+		t.Errorf("expected %v without the scale, got %v", m, result)
+	}
+}
+
+func TestBlendDualQuatsKeepsVolume(t *testing.T) {
+	//Half way between no twist and a half turn of twist around x
+	twist := mgl32.HomogRotate3DX(mgl32.DegToRad(180))
+	first, second := DualQuatFromMat4(mgl32.Ident4()), DualQuatFromMat4(twist)
+	second.Real, second.Dual = second.Real.Scale(-1), second.Dual.Scale(-1)
+	point := mgl32.Vec3{0, 1, 0}
+	blended := BlendDualQuats([]DualQuat{first, second}, []float32{0.5, 0.5}).Mat4().Mul4x1(point.Vec4(1)).Vec3()
+	if !mgl32.FloatEqualThreshold(blended.Len(), 1, 1e-5) || mgl32.Abs(blended.Y()) > 1e-5 {
+		t.Errorf("expected the point a quarter turn around x, got %v", blended)
+	}
+	//Linear blending collapses the same point onto the axis
+	linear := mgl32.Ident4().Mul(0.5).Add(twist.Mul(0.5)).Mul4x1(point.Vec4(1)).Vec3()
+	if linear.Len() > 1e-5 {
+		t.Errorf("expected linear blending to collapse, got %v", linear)
+	}
+}
+
+func TestAnimatorDualQuaternions(t *testing.T) {
+	clip := testClip("walk", 2, 3)
+	animator, err := NewAnimator(testSkeleton(2), []Animation{clip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(&ClipNode{Animation: 0})
+	animator.Update(0.25)
+	for i := range animator.GlobalPoseMatrices {
+		if result := animator.GlobalDualQuaternions[i].Mat4(); !result.ApproxEqualThreshold(animator.GlobalPoseMatrices[i], 1e-5) {
+			t.Errorf("bone %v: expected %v, got %v", i, animator.GlobalPoseMatrices[i], result)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package anim
+
+import "math"
+
+// Event marks a point of interest at a clip relative Time, like a footstep
+type Event struct {
+	Name string
+	Time float32
+}
+
+// FiredEvent is an event that was crossed by the last Update, Weight is how
+// much its clip contributed to the pose, summed over the nodes that played it
+type FiredEvent struct {
+	Event
+	Animation int
+	Weight    float32
+}
+
+// Events returns the events crossed by the last Update, the slice is reused by
+// the next one
+func (a *Animator) Events() []FiredEvent {
+	return a.events
+}
+
+// fireEvents reports the events crossed when moving from the previous to the
+// current unwrapped clip time, including an event at current but not one at
+// previous so that it is not reported twice. A looping clip can cross the same
+// event several times, it is reported once. A ping pong clip passes its events
+// going forward and again on the way back, mirrored in a cycle of twice the
+// duration
+func (a *Animator) fireEvents(animationIndex int, previous, current float32, mode LoopMode, weight float32) {
+	animation := &a.animations[animationIndex]
+	if weight <= 0 || previous == current {
+		return
+	}
+	backwards := current < previous
+	for _, event := range animation.Events {
+		crossed := false
+		if mode == LoopRepeat && animation.Duration > 0 {
+			crossed = repetitions(current, event.Time, animation.Duration, backwards) != repetitions(previous, event.Time, animation.Duration, backwards)
+		} else if mode == LoopPingPong && animation.Duration > 0 {
+			cycle := 2 * animation.Duration
+			crossed = repetitions(current, event.Time, cycle, backwards) != repetitions(previous, event.Time, cycle, backwards) ||
+				repetitions(current, cycle-event.Time, cycle, backwards) != repetitions(previous, cycle-event.Time, cycle, backwards)
+		} else if backwards {
+			crossed = current <= event.Time && event.Time < previous
+		} else {
+			crossed = previous < event.Time && event.Time <= current
+		}
+		if crossed {
+			a.addEvent(FiredEvent{Event: event, Animation: animationIndex, Weight: weight})
+		}
+	}
+}
+
+// repetitions of a looping event up to t, excluding one exactly at t when
+// counting backwards
+func repetitions(t, eventTime, duration float32, backwards bool) float64 {
+	if backwards {
+		return math.Ceil(float64((t - eventTime) / duration))
+	}
+	return math.Floor(float64((t - eventTime) / duration))
+}
+
+func (a *Animator) addEvent(fired FiredEvent) {
+	for i := range a.events {
+		if a.events[i].Event == fired.Event && a.events[i].Animation == fired.Animation {
+			a.events[i].Weight += fired.Weight
+			return
+		}
+	}
+	a.events = append(a.events, fired)
+}
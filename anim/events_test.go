@@ -0,0 +1,103 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func eventNames(events []FiredEvent) []string {
+	var names []string
+	for _, event := range events {
+		names = append(names, event.Name)
+	}
+	return names
+}
+
+func sameNames(events []FiredEvent, names ...string) bool {
+	if len(events) != len(names) {
+		return false
+	}
+	for i := range names {
+		if events[i].Name != names[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEventsAcrossLoops(t *testing.T) {
+	clip := testClip("walk", 1, 0)
+	clip.Events = []Event{{Name: "left", Time: 0}, {Name: "right", Time: 0.5}}
+	animator, err := NewAnimator(testSkeleton(1), []Animation{clip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(&ClipNode{Animation: 0})
+	steps := []struct {
+		deltaTime float32
+		expected  []string
+	}{
+		{0.3, nil},
+		{0.2, []string{"right"}},
+		{0.4, nil},
+		{0.1, []string{"left"}},
+		//A long frame crosses both events, each is reported once
+		{2.75, []string{"left", "right"}},
+	}
+	for i, step := range steps {
+		animator.Update(step.deltaTime)
+		if !sameNames(animator.Events(), step.expected...) {
+			t.Errorf("step %v: expected %v, got %v", i, step.expected, eventNames(animator.Events()))
+		}
+	}
+	//Reversing at 3.75 seconds plays the clip back from 0.75
+	animator.SetPlaybackRate(0, -1)
+	animator.Update(0.25)
+	if !sameNames(animator.Events(), "right") {
+		t.Errorf("expected the right step backwards, got %v", eventNames(animator.Events()))
+	}
+	animator.Update(0.5)
+	if !sameNames(animator.Events(), "left") {
+		t.Errorf("expected the left step backwards, got %v", eventNames(animator.Events()))
+	}
+}
+
+func TestEventWeights(t *testing.T) {
+	walk, run := testClip("walk", 1, 0), testClip("run", 1, 1)
+	walk.Events = []Event{{Name: "step", Time: 0.5}}
+	run.Events = []Event{{Name: "step", Time: 0.5}}
+	jump := testClip("jump", 1, 0)
+	jump.Events = []Event{{Name: "land", Time: 0.5}}
+	animator, err := NewAnimator(testSkeleton(1), []Animation{walk, run, jump})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetLooping(0, false)
+	animator.SetBlendTree(&LerpNode{A: &ClipNode{Animation: 0}, B: &ClipNode{Animation: 1}, Weight: 0.25})
+	jumpLayer := animator.AddLayer(Layer{Node: &ClipNode{Animation: 2, Parameter: "jump"}, Weight: 0.5})
+	animator.SetParameter("jump", 0.75)
+	animator.Update(0.6)
+	events := animator.Events()
+	if len(events) != 2 || events[0].Animation != 0 || !mgl32.FloatEqual(events[0].Weight, 0.75) || events[1].Animation != 1 || !mgl32.FloatEqual(events[1].Weight, 0.25) {
+		t.Errorf("expected both steps weighted by the blend, got %+v", events)
+	}
+
+	//The jump layer was not sampled before, so it starts without a burst
+	animator.SetParameter("jump", 0.25)
+	animator.Update(0.1)
+	animator.SetParameter("jump", 0.5)
+	animator.Update(0.1)
+	if events := animator.Events(); len(events) != 1 || events[0].Name != "land" || events[0].Weight != 0.5 {
+		t.Errorf("expected the land event at the layer weight, got %+v", events)
+	}
+	animator.SetLayerWeight(jumpLayer, 0)
+	animator.SetParameter("jump", 0)
+	animator.Update(0.1)
+	animator.SetLayerWeight(jumpLayer, 1)
+	animator.SetParameter("jump", 1)
+	animator.Update(0.1)
+	if len(animator.Events()) != 0 {
+		t.Errorf("expected no events when a layer comes back, got %+v", animator.Events())
+	}
+}
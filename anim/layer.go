@@ -0,0 +1,71 @@
+package anim
+
+import "github.com/go-gl/mathgl/mgl32"
+
+type LayerMode int
+
+const (
+	//Override layers blend from the pose below them towards their own
+	LayerOverride LayerMode = iota
+	//Additive layers add their pose on top of the one below them
+	LayerAdditive
+)
+
+// Layer is applied over the animator's blend tree, and the layers added before
+// it, through its Mask at Weight. A nil Mask affects every bone
+type Layer struct {
+	Name   string
+	Node   BlendNode
+	Mode   LayerMode
+	Mask   *BoneMask
+	Weight float32
+}
+
+// AddLayer appends a layer and returns its index
+func (a *Animator) AddLayer(layer Layer) int {
+	a.layers = append(a.layers, layer)
+	a.layerClocks = append(a.layerClocks, newClock())
+	a.resizePosePool()
+	return len(a.layers) - 1
+}
+
+func (a *Animator) SetLayerWeight(index int, weight float32) {
+	a.layers[index].Weight = weight
+}
+
+func (a *Animator) LayerWeight(index int) float32 {
+	return a.layers[index].Weight
+}
+
+func (a *Animator) applyLayers() {
+	//Root motion only comes from the blend tree
+	a.evaluatingLayers = true
+	for i := range a.layers {
+		layer := &a.layers[i]
+		if layer.Weight <= 0 {
+			continue
+		}
+		a.clock = a.layerClocks[i]
+		layer.Node.evaluate(a, 1, layer.Weight)
+		blendMasked(&a.workingPoses[0], &a.workingPoses[1], layer.Mode, layer.Mask, layer.Weight)
+	}
+	a.clock = a.baseClock
+	a.evaluatingLayers = false
+}
+
+func blendMasked(base, layer *pose, mode LayerMode, mask *BoneMask, weight float32) {
+	for i := range base.translations {
+		t := weight * mask.weight(i)
+		if t == 0 {
+			continue
+		}
+		if mode == LayerAdditive {
+			base.translations[i] = base.translations[i].Add(layer.translations[i].Mul(t))
+			base.rotations[i] = base.rotations[i].Mul(nlerpQuat(mgl32.QuatIdent(), layer.rotations[i], t)).Normalize()
+		} else {
+			base.translations[i] = base.translations[i].Mul(1 - t).Add(layer.translations[i].Mul(t))
+			base.rotations[i] = slerpQuat(base.rotations[i], layer.rotations[i], t)
+			base.scales[i] = base.scales[i].Mul(1 - t).Add(layer.scales[i].Mul(t))
+		}
+	}
+}
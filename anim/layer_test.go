@@ -0,0 +1,99 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// branchingSkeleton has a root with two chains, 0 -> 1 -> 2 and 0 -> 3
+func branchingSkeleton() *Skeleton {
+	skeleton := testSkeleton(4)
+	skeleton.Bones[3].ParentIndex = 0
+	return skeleton
+}
+
+func TestBoneMasks(t *testing.T) {
+	skeleton := branchingSkeleton()
+	if bones := skeleton.Subtree(1); len(bones) != 2 || bones[0] != 1 || bones[1] != 2 {
+		t.Errorf("expected the subtree of bone 1 to be [1 2], got %v", bones)
+	}
+	upper, err := skeleton.AddSubtreeMask("upper", "b", 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upper.Weights[0] != 0 || upper.Weights[1] != 0.5 || upper.Weights[2] != 0.5 || upper.Weights[3] != 0 {
+		t.Errorf("unexpected subtree weights %v", upper.Weights)
+	}
+	explicit, err := skeleton.AddBoneMask("explicit", map[string]float32{"a": 0.25, "d": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if explicit.Weights[0] != 0.25 || explicit.Weights[3] != 1 || skeleton.Masks["upper"] != upper {
+		t.Errorf("unexpected masks %v", skeleton.Masks)
+	}
+	if _, err := skeleton.AddSubtreeMask("missing", "z", 1); err == nil {
+		t.Errorf("expected an error for an unknown bone")
+	}
+}
+
+func TestAnimationMask(t *testing.T) {
+	skeleton := branchingSkeleton()
+	//Bone 1 turns and bone 3 turns back by the second key, the others stay put
+	turn := Animation{Name: "turn", Duration: 1, Keyframes: make([]Keyframe, 2)}
+	for k := range turn.Keyframes {
+		turn.Keyframes[k] = Keyframe{SampleTime: float32(k), Transforms: make([]Transform, 4)}
+		for b := range turn.Keyframes[k].Transforms {
+			turn.Keyframes[k].Transforms[b] = IdentityTransform()
+		}
+	}
+	turn.Keyframes[0].Transforms[1] = TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -60, 0})
+	turn.Keyframes[0].Transforms[3] = TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 45, 0})
+	mask, err := skeleton.AddAnimationMask("turn", &turn, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mask.Weights[0] != 0 || mask.Weights[1] != 0.5 || mask.Weights[2] != 0 || mask.Weights[3] != 0.5 || skeleton.Masks["turn"] != mask {
+		t.Errorf("expected the turned bones 1 and 3, got %v", mask.Weights)
+	}
+	if turn.Tracks != nil {
+		t.Errorf("expected the animation to be left as it was")
+	}
+	if _, err := skeleton.AddAnimationMask("still", &Animation{Name: "still", Keyframes: turn.Keyframes[1:]}, 1); err == nil {
+		t.Errorf("expected an error for an animation that moves no bone")
+	}
+	if _, err := skeleton.AddAnimationMask("wide", &Animation{Name: "wide", Tracks: []Track{NewTrack(7, []float32{0}, []Transform{TransformFromEuler([3]float32{1, 1, 1}, [3]float32{1, 0, 0}, [3]float32{})})}}, 1); err == nil {
+		t.Errorf("expected an error for a bone outside the skeleton")
+	}
+}
+
+func TestLayers(t *testing.T) {
+	skeleton := branchingSkeleton()
+	mask, err := skeleton.AddSubtreeMask("arm", "d", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator, err := NewAnimator(skeleton, []Animation{testClip("run", 4, 0), testClip("wave", 4, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//The skeleton is shared by the animator's rig from now on
+	if _, err := skeleton.AddBoneMask("late", map[string]float32{"a": 1}); err == nil || skeleton.Masks["late"] != nil {
+		t.Errorf("expected masks added after NewAnimator to be rejected")
+	}
+	animator.SetBlendTree(&ClipNode{Animation: 0})
+	override := animator.AddLayer(Layer{Name: "wave", Node: &ClipNode{Animation: 1}, Mask: mask, Weight: 0.5})
+	animator.AddLayer(Layer{Name: "lean", Node: &ClipNode{Animation: 1}, Mode: LayerAdditive, Mask: skeleton.Masks["arm"], Weight: 1})
+	animator.Update(0.1)
+	//Bones outside of the mask keep running, the arm gets half the wave plus the additive offset
+	for bone, expected := range []float32{0, 0, 0, 3} {
+		if x := animator.GlobalPoseMatrices[bone].Col(3).X(); !mgl32.FloatEqual(x, expected) {
+			t.Errorf("bone %v: expected %v, got %v", bone, expected, x)
+		}
+	}
+	animator.SetLayerWeight(override, 0)
+	animator.Update(0.1)
+	if x := animator.GlobalPoseMatrices[3].Col(3).X(); !mgl32.FloatEqual(x, 2) {
+		t.Errorf("expected only the additive layer, got %v", x)
+	}
+}
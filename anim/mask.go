@@ -0,0 +1,125 @@
+package anim
+
+import "fmt"
+
+// BoneMask weighs how much a masked blend affects each bone of a skeleton,
+// Weights is indexed by bone and bones with weight 0 are left untouched
+type BoneMask struct {
+	Name    string
+	Weights []float32
+}
+
+// BoneIndex returns the index of the named bone, or -1 when there is none
+func (s *Skeleton) BoneIndex(name string) int {
+	for i := range s.Bones {
+		if s.Bones[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Subtree returns the bone and all of its descendants
+func (s *Skeleton) Subtree(boneIndex int) []int {
+	bones := []int{boneIndex}
+	for i := 0; i < len(bones); i++ {
+		for j := range s.Bones {
+			if j != s.RootIndex && s.Bones[j].ParentIndex == bones[i] {
+				bones = append(bones, j)
+			}
+		}
+	}
+	return bones
+}
+
+// AddSubtreeMask adds a mask named name that weighs the subtree of rootBone by
+// weight
+func (s *Skeleton) AddSubtreeMask(name, rootBone string, weight float32) (*BoneMask, error) {
+	if err := s.checkUnshared(name); err != nil {
+		return nil, err
+	}
+	root := s.BoneIndex(rootBone)
+	if root < 0 {
+		return nil, fmt.Errorf("anim: mask %v: unknown bone %v", name, rootBone)
+	}
+	mask := &BoneMask{Name: name, Weights: make([]float32, len(s.Bones))}
+	for _, bone := range s.Subtree(root) {
+		mask.Weights[bone] = weight
+	}
+	s.addMask(mask)
+	return mask, nil
+}
+
+// AddBoneMask adds a mask named name with an explicit weight per bone name.
+// Like the other masks it has to be added before the skeleton is given to
+// NewRig or NewAnimator: the animators of a rig read the skeleton from many
+// goroutines, so a shared skeleton rejects new masks
+func (s *Skeleton) AddBoneMask(name string, weights map[string]float32) (*BoneMask, error) {
+	if err := s.checkUnshared(name); err != nil {
+		return nil, err
+	}
+	mask := &BoneMask{Name: name, Weights: make([]float32, len(s.Bones))}
+	for boneName, weight := range weights {
+		bone := s.BoneIndex(boneName)
+		if bone < 0 {
+			return nil, fmt.Errorf("anim: mask %v: unknown bone %v", name, boneName)
+		}
+		mask.Weights[bone] = weight
+	}
+	s.addMask(mask)
+	return mask, nil
+}
+
+// AddAnimationMask adds a mask named name that weighs by weight the bones the
+// animation moves away from the identity transform, the bones an additive clip
+// changes
+func (s *Skeleton) AddAnimationMask(name string, animation *Animation, weight float32) (*BoneMask, error) {
+	if err := s.checkUnshared(name); err != nil {
+		return nil, err
+	}
+	if len(animation.Tracks) == 0 {
+		withTracks := *animation
+		withTracks.BuildTracks()
+		animation = &withTracks
+	}
+	mask := &BoneMask{Name: name, Weights: make([]float32, len(s.Bones))}
+	animated := 0
+	for i := range animation.Tracks {
+		track := &animation.Tracks[i]
+		if track.isIdentity() {
+			continue
+		}
+		if track.BoneIndex < 0 || track.BoneIndex >= len(s.Bones) {
+			return nil, fmt.Errorf("anim: mask %v: %v animates bone %v, but the skeleton has %v bones", name, animation.Name, track.BoneIndex, len(s.Bones))
+		}
+		mask.Weights[track.BoneIndex] = weight
+		animated++
+	}
+	if animated == 0 {
+		return nil, fmt.Errorf("anim: mask %v: %v animates no bone", name, animation.Name)
+	}
+	s.addMask(mask)
+	return mask, nil
+}
+
+func (s *Skeleton) checkUnshared(name string) error {
+	if s.shared {
+		return fmt.Errorf("anim: mask %v: the skeleton is shared by a rig, add masks before NewRig", name)
+	}
+	return nil
+}
+
+func (s *Skeleton) addMask(mask *BoneMask) {
+	if s.Masks == nil {
+		s.Masks = make(map[string]*BoneMask)
+	}
+	s.Masks[mask.Name] = mask
+}
+
+// weight of a bone, a nil mask lets every bone through
+func (m *BoneMask) weight(boneIndex int) float32 {
+	if m == nil {
+		return 1
+	}
+	return m.Weights[boneIndex]
+}
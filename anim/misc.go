@@ -1,51 +1,101 @@
 package anim
 
 import (
+	"math"
+
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-func (a *Animation) linearSample(t float32, result *Keyframe) {
-	count := len(a.Keyframes)
-	for i := 0; i < count-1; i++ {
-		if a.Keyframes[i+1].SampleTime >= t {
-			lerpKeyframe(&a.Keyframes[i], &a.Keyframes[i+1], (t-a.Keyframes[i].SampleTime)/(a.Keyframes[i+1].SampleTime-a.Keyframes[i].SampleTime), result)
-			return
-		}
-	}
+// TransformFromEuler builds a Transform from euler angles given in degrees, applied in X, Y, Z order
+func TransformFromEuler(scale, translate, rotation [3]float32) Transform {
+	rotateX := mgl32.QuatRotate(mgl32.DegToRad(rotation[0]), mgl32.Vec3{1, 0, 0})
+	rotateY := mgl32.QuatRotate(mgl32.DegToRad(rotation[1]), mgl32.Vec3{0, 1, 0})
+	rotateZ := mgl32.QuatRotate(mgl32.DegToRad(rotation[2]), mgl32.Vec3{0, 0, 1})
+	return Transform{Scale: scale, Translate: translate, Rotation: rotateZ.Mul(rotateY.Mul(rotateX))}
 }
 
-func lerpKeyframe(first, second *Keyframe, t float32, result *Keyframe) {
-	for i := 0; i < len(first.Transforms); i++ {
-		lerpTransform(&first.Transforms[i], &second.Transforms[i], t, &result.Transforms[i])
-	}
+// IdentityTransform returns the transform of a bone resting in its bind pose
+func IdentityTransform() Transform {
+	return Transform{Scale: [3]float32{1, 1, 1}, Rotation: mgl32.QuatIdent()}
 }
 
 func lerpTransform(first, second *Transform, t float32, result *Transform) {
 	result.Translate[0] = first.Translate[0]*(1-t) + second.Translate[0]*t
 	result.Translate[1] = first.Translate[1]*(1-t) + second.Translate[1]*t
 	result.Translate[2] = first.Translate[2]*(1-t) + second.Translate[2]*t
-	result.Rotation[0] = first.Rotation[0]*(1-t) + second.Rotation[0]*t
-	result.Rotation[1] = first.Rotation[1]*(1-t) + second.Rotation[1]*t
-	result.Rotation[2] = first.Rotation[2]*(1-t) + second.Rotation[2]*t
+	result.Rotation = slerpQuat(first.Rotation, second.Rotation, t)
 	result.Scale[0] = first.Scale[0]*(1-t) + second.Scale[0]*t
 	result.Scale[1] = first.Scale[1]*(1-t) + second.Scale[1]*t
 	result.Scale[2] = first.Scale[2]*(1-t) + second.Scale[2]*t
 }
 
+// The additive rotation is scaled by t and applied in the base transform's local space
 func addTransforms(first, second *Transform, t float32, result *Transform) {
 	result.Translate[0] = first.Translate[0] + second.Translate[0]*t
 	result.Translate[1] = first.Translate[1] + second.Translate[1]*t
 	result.Translate[2] = first.Translate[2] + second.Translate[2]*t
-	result.Rotation[0] = first.Rotation[0] + second.Rotation[0]*t
-	result.Rotation[1] = first.Rotation[1] + second.Rotation[1]*t
-	result.Rotation[2] = first.Rotation[2] + second.Rotation[2]*t
+	result.Rotation = first.Rotation.Mul(nlerpQuat(mgl32.QuatIdent(), second.Rotation, t)).Normalize()
+	result.Scale = first.Scale
+}
+
+// slerpQuat interpolates along the shortest arc, falling back to nlerp for nearly parallel rotations
+func slerpQuat(first, second mgl32.Quat, t float32) mgl32.Quat {
+	dot := first.Dot(second)
+	if dot < 0 {
+		second = second.Scale(-1)
+		dot = -dot
+	}
+	if dot > 0.9995 {
+		return lerpQuat(first, second, t).Normalize()
+	}
+	theta := math.Acos(float64(dot))
+	sinTheta := math.Sin(theta)
+	firstWeight := float32(math.Sin((1-float64(t))*theta) / sinTheta)
+	secondWeight := float32(math.Sin(float64(t)*theta) / sinTheta)
+	return first.Scale(firstWeight).Add(second.Scale(secondWeight))
+}
+
+// nlerpQuat is the cheaper, non constant velocity alternative to slerpQuat
+func nlerpQuat(first, second mgl32.Quat, t float32) mgl32.Quat {
+	if first.Dot(second) < 0 {
+		second = second.Scale(-1)
+	}
+	return lerpQuat(first, second, t).Normalize()
+}
+
+func lerpQuat(first, second mgl32.Quat, t float32) mgl32.Quat {
+	return mgl32.Quat{
+		W: first.W*(1-t) + second.W*t,
+		V: mgl32.Vec3{
+			first.V[0]*(1-t) + second.V[0]*t,
+			first.V[1]*(1-t) + second.V[1]*t,
+			first.V[2]*(1-t) + second.V[2]*t,
+		},
+	}
 }
 
 func TransformToMat4(t Transform) mgl32.Mat4 {
 	translate := mgl32.Translate3D(t.Translate[0], t.Translate[1], t.Translate[2])
 	scale := mgl32.Scale3D(t.Scale[0], t.Scale[1], t.Scale[2])
-	rotateX := mgl32.HomogRotate3DX(mgl32.DegToRad(t.Rotation[0]))
-	rotateY := mgl32.HomogRotate3DY(mgl32.DegToRad(t.Rotation[1]))
-	rotateZ := mgl32.HomogRotate3DZ(mgl32.DegToRad(t.Rotation[2]))
-	return translate.Mul4(rotateZ.Mul4(rotateY.Mul4(rotateX.Mul4(scale))))
+	return translate.Mul4(t.Rotation.Mat4().Mul4(scale))
+}
+
+// Mat4ToTransform decomposes an affine matrix without shear back into a Transform
+func Mat4ToTransform(m mgl32.Mat4) Transform {
+	scale := [3]float32{m.Col(0).Vec3().Len(), m.Col(1).Vec3().Len(), m.Col(2).Vec3().Len()}
+	if m.Mat3().Det() < 0 {
+		scale[0] = -scale[0]
+	}
+	rotation := mgl32.Ident4()
+	for c := 0; c < 3; c++ {
+		if scale[c] != 0 {
+			rotation.SetCol(c, m.Col(c).Mul(1/scale[c]))
+		}
+	}
+	rotation.SetCol(3, mgl32.Vec4{0, 0, 0, 1})
+	return Transform{
+		Scale:     scale,
+		Translate: [3]float32{m[12], m[13], m[14]},
+		Rotation:  mgl32.Mat4ToQuat(rotation).Normalize(),
+	}
 }
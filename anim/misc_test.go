@@ -0,0 +1,56 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestTransformFromEulerMatchesMatrixOrder(t *testing.T) {
+	rotation := [3]float32{-20, 35, 80}
+	transform := TransformFromEuler([3]float32{1, 2, 1}, [3]float32{0.5, 0, -1}, rotation)
+	expected := mgl32.Translate3D(0.5, 0, -1).Mul4(
+		mgl32.HomogRotate3DZ(mgl32.DegToRad(rotation[2])).Mul4(
+			mgl32.HomogRotate3DY(mgl32.DegToRad(rotation[1])).Mul4(
+				mgl32.HomogRotate3DX(mgl32.DegToRad(rotation[0])).Mul4(
+					mgl32.Scale3D(1, 2, 1)))))
+	if result := TransformToMat4(transform); !result.ApproxEqualThreshold(expected, 1e-5) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestSlerpTakesShortestPath(t *testing.T) {
+	from := mgl32.QuatRotate(mgl32.DegToRad(10), mgl32.Vec3{0, 1, 0})
+	to := mgl32.QuatRotate(mgl32.DegToRad(50), mgl32.Vec3{0, 1, 0}).Scale(-1)
+	expected := mgl32.QuatRotate(mgl32.DegToRad(30), mgl32.Vec3{0, 1, 0})
+	for _, result := range []mgl32.Quat{slerpQuat(from, to, 0.5), nlerpQuat(from, to, 0.5)} {
+		if !result.OrientationEqualThreshold(expected, 1e-5) {
+			t.Errorf("expected %v, got %v", expected, result)
+		}
+	}
+}
+
+func TestLerpTransformAcrossLargeAngle(t *testing.T) {
+	first := TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 170, 0})
+	second := TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -170, 0})
+	var result Transform
+	lerpTransform(&first, &second, 0.5, &result)
+	expected := mgl32.QuatRotate(mgl32.DegToRad(180), mgl32.Vec3{0, 1, 0})
+	if !result.Rotation.OrientationEqualThreshold(expected, 1e-5) {
+		t.Errorf("expected %v, got %v", expected, result.Rotation)
+	}
+}
+
+func TestAddTransformsZeroWeightKeepsBase(t *testing.T) {
+	base := TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 1, 0}, [3]float32{10, 20, 30})
+	additive := TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 45})
+	var result Transform
+	addTransforms(&base, &additive, 0, &result)
+	if !result.Rotation.OrientationEqualThreshold(base.Rotation, 1e-6) || result.Translate != base.Translate {
+		t.Errorf("expected %v, got %v", base, result)
+	}
+	addTransforms(&base, &additive, 1, &result)
+	if expected := base.Rotation.Mul(additive.Rotation); !result.Rotation.OrientationEqualThreshold(expected, 1e-5) {
+		t.Errorf("expected %v, got %v", expected, result.Rotation)
+	}
+}
@@ -0,0 +1,227 @@
+package anim
+
+import "math"
+
+// LoopMode is what a clip does when its time runs past either end
+type LoopMode int
+
+const (
+	//Repeat clips start over from the other end
+	LoopRepeat LoopMode = iota
+	//Ping pong clips turn around and play back the way they came
+	LoopPingPong
+	//Clamp clips hold their first or last frame
+	LoopClamp
+)
+
+// clock is the time a blend tree plays at. The animator's tree runs on its
+// base clock and every layer has a clock of its own, so a layer can be slowed
+// down or paused without touching the rest of the character
+type clock struct {
+	time     float32
+	previous float32
+	scale    float32
+	paused   bool
+}
+
+func newClock() *clock {
+	return &clock{scale: 1}
+}
+
+func (c *clock) advance(deltaTime float32) {
+	c.previous = c.time
+	if !c.paused {
+		c.time += deltaTime * c.scale
+	}
+}
+
+// wrap folds an unwrapped clip time into the clip
+func (m LoopMode) wrap(t, duration float32) float32 {
+	switch m {
+	case LoopPingPong:
+		if duration <= 0 {
+			return 0
+		}
+		if t = wrapTime(t, 2*duration, true); t > duration {
+			return 2*duration - t
+		}
+		return t
+	case LoopClamp:
+		return wrapTime(t, duration, false)
+	}
+	return wrapTime(t, duration, true)
+}
+
+// clipTime is the clip's unwrapped time at its clock's time. Clips are only
+// advanced when they are sampled, so the time is extrapolated from the last
+// one. Clamped clips do not run past their ends, so they turn back at once
+func (a *Animator) clipTime(index int) float32 {
+	state := &a.animationStates[index]
+	if state.paused {
+		return state.time
+	}
+	return state.loopMode.clamp(state.time+state.playbackRate*(state.clock.time-state.clockTime), a.animations[index].Duration)
+}
+
+// settle stores the current time, before changing how the clip advances
+func (a *Animator) settle(index int) {
+	state := &a.animationStates[index]
+	state.time, state.clockTime = a.clipTime(index), state.clock.time
+}
+
+// advanceClip moves a clip to the time of the clock evaluating it and returns
+// its unwrapped times on the previous frame and now
+func (a *Animator) advanceClip(index int) (previous, current float32) {
+	state := &a.animationStates[index]
+	if state.clock != a.clock {
+		//A clip played by another layer carries on from where it was at the
+		//start of the frame
+		if !state.paused {
+			state.time += state.playbackRate * (state.clock.previous - state.clockTime)
+		}
+		state.clock, state.clockTime = a.clock, a.clock.previous
+	}
+	if state.paused {
+		state.clockTime = a.clock.time
+		return state.time, state.time
+	}
+	current = state.time + state.playbackRate*(a.clock.time-state.clockTime)
+	previous = current - state.playbackRate*(a.clock.time-a.clock.previous)
+	duration := a.animations[index].Duration
+	current = state.loopMode.clamp(current, duration)
+	//Clamped clips that reached their end stay there, a start of the clip in
+	//this frame still fires its first events
+	if state.loopMode == LoopClamp && state.playbackRate > 0 && previous > duration {
+		previous = duration
+	} else if state.loopMode == LoopClamp && state.playbackRate < 0 && previous < 0 {
+		previous = 0
+	}
+	state.time, state.clockTime = current, a.clock.time
+	return previous, current
+}
+
+func (m LoopMode) clamp(t, duration float32) float32 {
+	if m != LoopClamp {
+		return t
+	} else if t < 0 {
+		return 0
+	} else if t > duration {
+		return duration
+	}
+	return t
+}
+
+// SetTimeScale speeds up or slows down the whole animator, layers included.
+// 1 is the normal speed and 0 freezes it
+func (a *Animator) SetTimeScale(scale float32) {
+	a.timeScale = scale
+}
+
+func (a *Animator) TimeScale() float32 {
+	return a.timeScale
+}
+
+// SetLayerTimeScale sets how fast a layer's clock runs, on top of the
+// animator's time scale
+func (a *Animator) SetLayerTimeScale(index int, scale float32) {
+	a.layerClocks[index].scale = scale
+}
+
+// SetLayerPaused stops or restarts a layer's clock, the layer keeps its pose
+func (a *Animator) SetLayerPaused(index int, paused bool) {
+	a.layerClocks[index].paused = paused
+}
+
+// Play starts a clip over from its first frame, or from its last one when it
+// plays in reverse
+func (a *Animator) Play(index int) {
+	state := &a.animationStates[index]
+	state.time, state.clockTime, state.paused = a.startTime(index), state.clock.time, false
+}
+
+// Stop pauses a clip and rewinds it to where Play starts it
+func (a *Animator) Stop(index int) {
+	state := &a.animationStates[index]
+	state.time, state.clockTime, state.paused = a.startTime(index), state.clock.time, true
+}
+
+// Pause holds a clip at its current time until Resume
+func (a *Animator) Pause(index int) {
+	a.settle(index)
+	a.animationStates[index].paused = true
+}
+
+func (a *Animator) Resume(index int) {
+	state := &a.animationStates[index]
+	state.clockTime, state.paused = state.clock.time, false
+}
+
+// Seek jumps to a time of the clip in seconds, the events in between are not
+// fired
+func (a *Animator) Seek(index int, t float32) {
+	state := &a.animationStates[index]
+	state.time, state.clockTime = state.loopMode.clamp(t, a.animations[index].Duration), state.clock.time
+}
+
+// Reverse flips the direction a clip plays in, from where it is now
+func (a *Animator) Reverse(index int) {
+	a.SetPlaybackRate(index, -a.animationStates[index].playbackRate)
+}
+
+// IsPlaying tells whether a clip is advancing, clamped clips stop at their end
+func (a *Animator) IsPlaying(index int) bool {
+	state := &a.animationStates[index]
+	if state.paused || state.playbackRate == 0 {
+		return false
+	}
+	if state.loopMode != LoopClamp {
+		return true
+	}
+	t := a.clipTime(index)
+	return (state.playbackRate > 0 && t < a.animations[index].Duration) || (state.playbackRate < 0 && t > 0)
+}
+
+// ClipTime returns a clip's time in seconds, between 0 and its duration
+func (a *Animator) ClipTime(index int) float32 {
+	state := &a.animationStates[index]
+	return state.loopMode.wrap(a.clipTime(index), a.animations[index].Duration)
+}
+
+// NormalizedTime returns a clip's time as a fraction of its duration
+func (a *Animator) NormalizedTime(index int) float32 {
+	duration := a.animations[index].Duration
+	if duration <= 0 {
+		return 0
+	}
+	return a.ClipTime(index) / duration
+}
+
+// RemainingTime returns the seconds until a clip reaches the end it plays
+// towards: its end, the end of the cycle for repeating clips or the next turn
+// for ping pong ones. It is infinite for clips that do not advance
+func (a *Animator) RemainingTime(index int) float32 {
+	state := &a.animationStates[index]
+	duration := a.animations[index].Duration
+	speed := state.playbackRate * a.timeScale * state.clock.scale
+	if state.paused || state.clock.paused || speed == 0 {
+		return float32(math.Inf(1))
+	}
+	t := a.clipTime(index)
+	forward := state.playbackRate > 0
+	if state.loopMode == LoopPingPong && duration > 0 && wrapTime(t, 2*duration, true) > duration {
+		forward = !forward
+	}
+	left := state.loopMode.wrap(t, duration)
+	if forward {
+		left = duration - left
+	}
+	return left / float32(math.Abs(float64(speed)))
+}
+
+// startTime is the start of a clip in the direction it plays
+func (a *Animator) startTime(index int) float32 {
+	if a.animationStates[index].playbackRate < 0 {
+		return a.animations[index].Duration
+	}
+	return 0
+}
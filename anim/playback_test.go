@@ -0,0 +1,114 @@
+package anim
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestPlaybackControl(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(1), []Animation{clockClip("walk", 1, Event{Name: "mid", Time: 0.5})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(&ClipNode{Animation: 0})
+	step := func(deltaTime, x float32, events ...string) {
+		t.Helper()
+		animator.Update(deltaTime)
+		if result := animator.GlobalPoseMatrices[0].Col(3).X(); mgl32.Abs(result-x) > 1e-5 {
+			t.Errorf("expected the clip at %v, got %v", x, result)
+		}
+		if !sameNames(animator.Events(), events...) {
+			t.Errorf("expected the events %v, got %v", events, eventNames(animator.Events()))
+		}
+	}
+	expectRemaining := func(expected float32) {
+		t.Helper()
+		if result := animator.RemainingTime(0); mgl32.Abs(result-expected) > 1e-5 {
+			t.Errorf("expected %v seconds left, got %v", expected, result)
+		}
+	}
+
+	step(0.25, 0.25)
+	if normalized := animator.NormalizedTime(0); mgl32.Abs(normalized-0.25) > 1e-5 {
+		t.Errorf("expected a quarter of the clip, got %v", normalized)
+	}
+	expectRemaining(0.75)
+	//Slow motion
+	animator.SetTimeScale(0.5)
+	step(0.5, 0.5, "mid")
+	expectRemaining(1)
+	animator.SetTimeScale(1)
+
+	animator.Pause(0)
+	step(1, 0.5)
+	if animator.IsPlaying(0) || !math.IsInf(float64(animator.RemainingTime(0)), 1) {
+		t.Errorf("expected a paused clip")
+	}
+	animator.Resume(0)
+	step(0.1, 0.6)
+	//Seeking skips the events in between
+	animator.Seek(0, 0.2)
+	step(0.1, 0.3)
+	animator.Reverse(0)
+	step(0.2, 0.1)
+	expectRemaining(0.1)
+	animator.Reverse(0)
+	animator.Stop(0)
+	step(0.3, 0)
+	animator.Play(0)
+	step(0.3, 0.3)
+
+	//Clamped clips hold their last frame and turn back from it at once
+	animator.SetLoopMode(0, LoopClamp)
+	step(1, 1, "mid")
+	if animator.IsPlaying(0) {
+		t.Errorf("expected a clamped clip to stop at its end")
+	}
+	step(1, 1)
+	animator.Reverse(0)
+	step(0.25, 0.75)
+	if !animator.IsPlaying(0) {
+		t.Errorf("expected the reversed clip to play")
+	}
+
+	//Ping pong clips pass their events both ways
+	animator.SetLoopMode(0, LoopPingPong)
+	animator.Reverse(0)
+	animator.Seek(0, 0)
+	step(0.75, 0.75, "mid")
+	step(0.5, 0.75)
+	step(0.5, 0.25, "mid")
+	expectRemaining(0.25)
+	step(0.5, 0.25)
+	expectRemaining(0.75)
+}
+
+func TestLayerClocks(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(1), []Animation{clockClip("walk", 1), clockClip("wave", 4)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(&ClipNode{Animation: 0})
+	layer := animator.AddLayer(Layer{Node: &ClipNode{Animation: 1}, Weight: 1})
+	x := func() float32 {
+		return animator.GlobalPoseMatrices[0].Col(3).X()
+	}
+	animator.SetLayerTimeScale(layer, 0.5)
+	animator.Update(1)
+	if mgl32.Abs(x()-0.5) > 1e-5 {
+		t.Errorf("expected the layer at half speed, got %v", x())
+	}
+	animator.SetLayerPaused(layer, true)
+	animator.Update(0.25)
+	if mgl32.Abs(x()-0.5) > 1e-5 {
+		t.Errorf("expected the paused layer to hold its pose, got %v", x())
+	}
+	if clipTime := animator.ClipTime(0); mgl32.Abs(clipTime-0.25) > 1e-5 {
+		t.Errorf("expected the base clip to keep playing, got %v", clipTime)
+	}
+	if remaining := animator.RemainingTime(1); !math.IsInf(float64(remaining), 1) {
+		t.Errorf("expected no end for a clip of a paused layer, got %v", remaining)
+	}
+}
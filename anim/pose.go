@@ -0,0 +1,145 @@
+package anim
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// pose is a local pose laid out as a structure of arrays indexed by bone, so
+// sampling and blending each run over one contiguous array at a time
+type pose struct {
+	translations []mgl32.Vec3
+	rotations    []mgl32.Quat
+	scales       []mgl32.Vec3
+}
+
+func newPose(boneCount int) pose {
+	p := pose{translations: make([]mgl32.Vec3, boneCount), rotations: make([]mgl32.Quat, boneCount), scales: make([]mgl32.Vec3, boneCount)}
+	p.reset()
+	return p
+}
+
+// reset puts every bone in its bind pose
+func (p *pose) reset() {
+	for i := range p.translations {
+		p.translations[i] = mgl32.Vec3{}
+	}
+	for i := range p.rotations {
+		p.rotations[i] = mgl32.QuatIdent()
+	}
+	for i := range p.scales {
+		p.scales[i] = mgl32.Vec3{1, 1, 1}
+	}
+}
+
+func (p *pose) copyFrom(other *pose) {
+	copy(p.translations, other.translations)
+	copy(p.rotations, other.rotations)
+	copy(p.scales, other.scales)
+}
+
+func (p *pose) transform(i int) Transform {
+	return Transform{Translate: p.translations[i], Rotation: p.rotations[i], Scale: p.scales[i]}
+}
+
+func (p *pose) setTransform(i int, t Transform) {
+	p.translations[i], p.rotations[i], p.scales[i] = t.Translate, t.Rotation, t.Scale
+}
+
+func (p *pose) setKeyframe(k *Keyframe) {
+	for i := range k.Transforms {
+		p.setTransform(i, k.Transforms[i])
+	}
+}
+
+// matrix is TransformToMat4 of a bone, built directly from the quaternion
+func (p *pose) matrix(i int) mgl32.Mat4 {
+	q, t, s := p.rotations[i], p.translations[i], p.scales[i]
+	w, x, y, z := q.W, q.V[0], q.V[1], q.V[2]
+	return mgl32.Mat4{
+		(1 - 2*y*y - 2*z*z) * s[0], (2*x*y + 2*w*z) * s[0], (2*x*z - 2*w*y) * s[0], 0,
+		(2*x*y - 2*w*z) * s[1], (1 - 2*x*x - 2*z*z) * s[1], (2*y*z + 2*w*x) * s[1], 0,
+		(2*x*z + 2*w*y) * s[2], (2*y*z - 2*w*x) * s[2], (1 - 2*x*x - 2*y*y) * s[2], 0,
+		t[0], t[1], t[2], 1,
+	}
+}
+
+func lerpPose(first, second *pose, t float32, result *pose) {
+	for i := range result.translations {
+		a, b := &first.translations[i], &second.translations[i]
+		result.translations[i] = mgl32.Vec3{a[0]*(1-t) + b[0]*t, a[1]*(1-t) + b[1]*t, a[2]*(1-t) + b[2]*t}
+	}
+	//A normalized lerp follows the same arc as a slerp, only not at constant
+	//speed, which a blend weight does not need. It saves the acos and sin per bone
+	for i := range result.rotations {
+		a, b := &first.rotations[i], &second.rotations[i]
+		s := t
+		if a.W*b.W+a.V[0]*b.V[0]+a.V[1]*b.V[1]+a.V[2]*b.V[2] < 0 {
+			s = -t
+		}
+		q := mgl32.Quat{W: a.W*(1-t) + b.W*s, V: mgl32.Vec3{a.V[0]*(1-t) + b.V[0]*s, a.V[1]*(1-t) + b.V[1]*s, a.V[2]*(1-t) + b.V[2]*s}}
+		inverseLength := 1 / float32(math.Sqrt(float64(q.W*q.W+q.V[0]*q.V[0]+q.V[1]*q.V[1]+q.V[2]*q.V[2])))
+		result.rotations[i] = mgl32.Quat{W: q.W * inverseLength, V: q.V.Mul(inverseLength)}
+	}
+	for i := range result.scales {
+		a, b := &first.scales[i], &second.scales[i]
+		result.scales[i] = mgl32.Vec3{a[0]*(1-t) + b[0]*t, a[1]*(1-t) + b[1]*t, a[2]*(1-t) + b[2]*t}
+	}
+}
+
+// addPose applies the additive pose scaled by t on top of the base, see
+// addTransforms
+func addPose(base, additive *pose, t float32, result *pose) {
+	for i := range result.translations {
+		a, b := &base.translations[i], &additive.translations[i]
+		result.translations[i] = mgl32.Vec3{a[0] + b[0]*t, a[1] + b[1]*t, a[2] + b[2]*t}
+	}
+	for i := range result.rotations {
+		result.rotations[i] = base.rotations[i].Mul(nlerpQuat(mgl32.QuatIdent(), additive.rotations[i], t)).Normalize()
+	}
+	copy(result.scales, base.scales)
+}
+
+// ParentFirstOrder returns the bone indices sorted so every bone comes after
+// its parent, starting at the root. It fails for bones whose parents do not
+// lead back to the root
+func (s *Skeleton) ParentFirstOrder() ([]int, error) {
+	if s.RootIndex < 0 || s.RootIndex >= len(s.Bones) {
+		return nil, fmt.Errorf("anim: root index %v is outside of the skeleton", s.RootIndex)
+	}
+	children := make([][]int, len(s.Bones))
+	for i := range s.Bones {
+		if i == s.RootIndex {
+			continue
+		}
+		parent := s.Bones[i].ParentIndex
+		if parent < 0 || parent >= len(s.Bones) {
+			return nil, fmt.Errorf("anim: bone %v has parent %v outside of the skeleton", s.Bones[i].Name, parent)
+		}
+		children[parent] = append(children[parent], i)
+	}
+	order := append(make([]int, 0, len(s.Bones)), s.RootIndex)
+	for i := 0; i < len(order); i++ {
+		order = append(order, children[order[i]]...)
+	}
+	if len(order) != len(s.Bones) {
+		return nil, fmt.Errorf("anim: %v bones are not connected to the root", len(s.Bones)-len(order))
+	}
+	return order, nil
+}
+
+// calcSkinningMatrices computes the global pose matrices of a local pose in a
+// single pass over the parent first order
+func calcSkinningMatrices(skeleton *Skeleton, order []int, p *pose, result []mgl32.Mat4) {
+	for _, i := range order {
+		bone := &skeleton.Bones[i]
+		local := bone.BindPose.Mul4(p.matrix(i)).Mul4(bone.InverseBindPose)
+		if i == skeleton.RootIndex {
+			result[i] = local
+		} else {
+			result[i] = result[bone.ParentIndex].Mul4(local)
+		}
+	}
+}
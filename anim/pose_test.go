@@ -0,0 +1,128 @@
+package anim
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestPoseMatrix(t *testing.T) {
+	transform := TransformFromEuler([3]float32{1, 2, 0.5}, [3]float32{3, -1, 2}, [3]float32{20, -70, 135})
+	p := newPose(1)
+	p.setTransform(0, transform)
+	if result, expected := p.matrix(0), TransformToMat4(transform); !result.ApproxEqualThreshold(expected, 1e-5) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestLerpPose(t *testing.T) {
+	first, second, result := newPose(2), newPose(2), newPose(2)
+	first.setTransform(0, TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 2, 0}, [3]float32{10, 0, 0}))
+	second.setTransform(0, TransformFromEuler([3]float32{3, 1, 1}, [3]float32{4, 2, 0}, [3]float32{50, 20, 0}))
+	//The second rotation is the first one in the other hemisphere
+	first.setTransform(1, TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 30, 0}))
+	second.setTransform(1, Transform{Scale: [3]float32{1, 1, 1}, Rotation: first.rotations[1].Scale(-1)})
+	lerpPose(&first, &second, 0.25, &result)
+	if result.translations[0] != (mgl32.Vec3{1, 2, 0}) || result.scales[0] != (mgl32.Vec3{1.5, 1, 1}) {
+		t.Errorf("expected a quarter of the way, got %v and %v", result.translations[0], result.scales[0])
+	}
+	//Off a slerp by the nlerp's uneven speed only
+	if angle := angleBetween(result.rotations[0], slerpQuat(first.rotations[0], second.rotations[0], 0.25)); angle > mgl32.DegToRad(0.5) {
+		t.Errorf("expected the rotation close to the slerp, got %v degrees off", mgl32.RadToDeg(angle))
+	}
+	if angle := angleBetween(result.rotations[1], first.rotations[1]); angle > 1e-3 || mgl32.Abs(result.rotations[1].Len()-1) > 1e-5 {
+		t.Errorf("expected the shortest path between the hemispheres, got %v", result.rotations[1])
+	}
+}
+
+func angleBetween(a, b mgl32.Quat) float32 {
+	dot := mgl32.Abs(a.Dot(b))
+	if dot > 1 {
+		dot = 1
+	}
+	return 2 * float32(math.Acos(float64(dot)))
+}
+
+func TestParentFirstOrder(t *testing.T) {
+	skeleton := benchSkeleton()
+	order, err := skeleton.ParentFirstOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make([]bool, len(skeleton.Bones))
+	for _, i := range order {
+		if i != skeleton.RootIndex && !seen[skeleton.Bones[i].ParentIndex] {
+			t.Fatalf("bone %v comes before its parent in %v", i, order)
+		}
+		seen[i] = true
+	}
+	if len(order) != len(skeleton.Bones) {
+		t.Errorf("expected every bone once, got %v", order)
+	}
+
+	//The linear pass matches the recursive one
+	animator, err := NewAnimator(skeleton, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := newLegacyPose(skeleton)
+	legacy.localPose = benchKeyframe(0.5)
+	legacy.calcGlobalPoseMatrices()
+	animator.localPose.setKeyframe(&legacy.localPose)
+	animator.CalcGlobalPoseMatrices()
+	for i := range legacy.globalPoseMatrices {
+		if !animator.GlobalPoseMatrices[i].ApproxEqualThreshold(legacy.globalPoseMatrices[i], 1e-4) {
+			t.Errorf("bone %v: expected %v, got %v", i, legacy.globalPoseMatrices[i], animator.GlobalPoseMatrices[i])
+		}
+	}
+
+	//Parents that never lead to the root are rejected
+	cycle := testSkeleton(3)
+	cycle.Bones[1].ParentIndex = 2
+	if _, err := NewAnimator(cycle, nil); err == nil {
+		t.Errorf("expected an error for a cycle")
+	}
+	cycle.Bones[1].ParentIndex = 5
+	if _, err := NewAnimator(cycle, nil); err == nil {
+		t.Errorf("expected an error for a parent outside of the skeleton")
+	}
+}
+
+func TestUpdateDoesNotAllocate(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(4), []Animation{testClip("idle", 4, 0), testClip("walk", 4, 1), testClip("run", 4, 2), testClip("wave", 4, 3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.animations[1].Events = []Event{{Name: "step", Time: 0.5}}
+	space, err := NewBlendSpace1D("speed", []BlendSample1D{{Position: 0, Node: &ClipNode{Animation: 0}}, {Position: 0.5, Node: &ClipNode{Animation: 1}}, {Position: 1, Node: &ClipNode{Animation: 2}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	machine := &StateMachine{
+		States: []State{{Name: "ground", Node: space}, {Name: "air", Node: &ClipNode{Animation: 3}}},
+		Transitions: []Transition{
+			{From: 0, To: 1, Conditions: []Condition{{Parameter: "airborne", Comparison: Greater, Value: 0.5}}, Duration: 0.2, Interruptible: true},
+			{From: 1, To: 0, Conditions: []Condition{{Parameter: "airborne", Comparison: Less, Value: 0.5}}, Duration: 0.2, Interruptible: true},
+		}}
+	animator.SetBlendTree(machine)
+	animator.AddLayer(Layer{Node: &ClipNode{Animation: 3}, Mode: LayerAdditive, Mask: &BoneMask{Weights: []float32{0, 1, 1, 0}}, Weight: 0.5})
+	animator.SetRootMotion(true)
+	frame := 0
+	update := func() {
+		frame++
+		animator.SetParameter("speed", float32(frame%10)/10)
+		animator.SetParameter("airborne", float32(frame/7%2))
+		animator.Update(0.05)
+	}
+	//The first frames size the event list and the state machine's frozen pose
+	for i := 0; i < 100; i++ {
+		update()
+	}
+	if allocs := testing.AllocsPerRun(100, update); allocs != 0 {
+		t.Errorf("expected no allocations per Update, got %v", allocs)
+	}
+	if !mgl32.FloatEqual(animator.GlobalDualQuaternions[0].Real.Len(), 1) {
+		t.Errorf("expected unit dual quaternions, got %v", animator.GlobalDualQuaternions[0])
+	}
+}
@@ -0,0 +1,174 @@
+package anim
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Retargeter converts clips authored on the Source skeleton into clips for the
+// Target skeleton. BoneMap holds the source bone of every target bone, or -1
+// for target bones that only follow their parent.
+//
+// Each mapped bone copies the model space rotation its source bone makes away
+// from the source's reference pose, which is its bind pose unless
+// SourceReference is set to the source pose that matches the target's bind
+// pose, like a T-pose keyframe for an A-pose target. Bone lengths are the
+// target's own, only the root moves, scaled by the ratio of the root heights.
+// Clips are sampled at their key times, or SampleRate times a second when set
+type Retargeter struct {
+	Source, Target  *Skeleton
+	BoneMap         []int
+	SourceReference *Keyframe
+	SampleRate      float32
+}
+
+// NewRetargeter maps the target's bones to the source bones of the same name,
+// the target root falls back to the source root when there is none
+func NewRetargeter(source, target *Skeleton) *Retargeter {
+	r := &Retargeter{Source: source, Target: target, BoneMap: make([]int, len(target.Bones))}
+	for i := range target.Bones {
+		r.BoneMap[i] = source.BoneIndex(target.Bones[i].Name)
+	}
+	if r.BoneMap[target.RootIndex] < 0 {
+		r.BoneMap[target.RootIndex] = source.RootIndex
+	}
+	return r
+}
+
+// MapBones overrides the mapping with a table from target to source bone names
+func (r *Retargeter) MapBones(names map[string]string) error {
+	for targetName, sourceName := range names {
+		target, source := r.Target.BoneIndex(targetName), r.Source.BoneIndex(sourceName)
+		if target < 0 {
+			return fmt.Errorf("anim: retarget: unknown target bone %v", targetName)
+		} else if source < 0 {
+			return fmt.Errorf("anim: retarget: unknown source bone %v", sourceName)
+		}
+		r.BoneMap[target] = source
+	}
+	return nil
+}
+
+// Retarget returns the animation converted into dense keyframes for the target
+func (r *Retargeter) Retarget(animation Animation) (Animation, error) {
+	if len(r.BoneMap) != len(r.Target.Bones) {
+		return Animation{}, fmt.Errorf("anim: retarget: bone map has %v bones, target has %v", len(r.BoneMap), len(r.Target.Bones))
+	}
+	for i, source := range r.BoneMap {
+		if source >= len(r.Source.Bones) {
+			return Animation{}, fmt.Errorf("anim: retarget: target bone %v maps to missing source bone %v", r.Target.Bones[i].Name, source)
+		}
+	}
+	if r.BoneMap[r.Target.RootIndex] < 0 {
+		return Animation{}, fmt.Errorf("anim: retarget: target root %v has no source bone", r.Target.Bones[r.Target.RootIndex].Name)
+	}
+	if len(animation.Tracks) == 0 {
+		animation.BuildTracks()
+	}
+	if err := animation.validateTracks(len(r.Source.Bones)); err != nil {
+		return Animation{}, fmt.Errorf("anim: retarget %v: %v", animation.Name, err)
+	}
+
+	sourceOrder, err := r.Source.ParentFirstOrder()
+	if err != nil {
+		return Animation{}, err
+	}
+	targetOrder, err := r.Target.ParentFirstOrder()
+	if err != nil {
+		return Animation{}, err
+	}
+	sourcePose := newPose(len(r.Source.Bones))
+	sourceGlobal := make([]mgl32.Mat4, len(r.Source.Bones))
+	referenceGlobal := make([]mgl32.Mat4, len(r.Source.Bones))
+	if r.SourceReference != nil {
+		if len(r.SourceReference.Transforms) != len(r.Source.Bones) {
+			return Animation{}, fmt.Errorf("anim: retarget: reference pose has %v transforms, source has %v bones", len(r.SourceReference.Transforms), len(r.Source.Bones))
+		}
+		reference := newPose(len(r.Source.Bones))
+		reference.setKeyframe(r.SourceReference)
+		calcSkinningMatrices(r.Source, sourceOrder, &reference, referenceGlobal)
+	} else {
+		for i := range referenceGlobal {
+			referenceGlobal[i] = mgl32.Ident4()
+		}
+	}
+
+	//The root's displacement is scaled by how much taller the target stands
+	scale := float32(1)
+	sourceRoot := &r.Source.Bones[r.BoneMap[r.Target.RootIndex]]
+	if height := sourceRoot.BindPose[13]; height != 0 {
+		scale = r.Target.Bones[r.Target.RootIndex].BindPose[13] / height
+	}
+
+	result := Animation{Name: animation.Name, Duration: animation.Duration, Events: append([]Event(nil), animation.Events...)}
+	targetGlobal := make([]mgl32.Mat4, len(r.Target.Bones))
+	for _, t := range r.sampleTimes(&animation) {
+		animation.sample(t, &sourcePose)
+		calcSkinningMatrices(r.Source, sourceOrder, &sourcePose, sourceGlobal)
+		keyframe := Keyframe{Transforms: make([]Transform, len(r.Target.Bones)), SampleTime: t}
+		for _, i := range targetOrder {
+			r.retargetBone(i, sourceGlobal, referenceGlobal, scale, targetGlobal, keyframe.Transforms)
+		}
+		result.Keyframes = append(result.Keyframes, keyframe)
+	}
+	return result, nil
+}
+
+// retargetBone sets the bone's skinning matrix and local transform, its
+// parent's skinning matrix has to be set before
+func (r *Retargeter) retargetBone(i int, sourceGlobal, referenceGlobal []mgl32.Mat4, scale float32, targetGlobal []mgl32.Mat4, transforms []Transform) {
+	bone := &r.Target.Bones[i]
+	parent := mgl32.Ident4()
+	if i != r.Target.RootIndex {
+		parent = targetGlobal[bone.ParentIndex]
+	}
+	source := r.BoneMap[i]
+	if source < 0 {
+		targetGlobal[i] = parent
+		transforms[i] = IdentityTransform()
+		return
+	}
+	//Rotate about the bone's head, which stays attached to the parent
+	delta := sourceGlobal[source].Mul4(referenceGlobal[source].Inv())
+	rotation := Mat4ToTransform(delta).Rotation.Mat4()
+	head := bone.BindPose.Col(3).Vec3()
+	position := parent.Mul4x1(head.Vec4(1)).Vec3()
+	if i == r.Target.RootIndex {
+		sourceHead := r.Source.Bones[source].BindPose.Col(3)
+		displacement := sourceGlobal[source].Mul4x1(sourceHead).Sub(referenceGlobal[source].Mul4x1(sourceHead)).Vec3()
+		position = head.Add(displacement.Mul(scale))
+	}
+	targetGlobal[i] = mgl32.Translate3D(position[0], position[1], position[2]).Mul4(rotation).Mul4(mgl32.Translate3D(-head[0], -head[1], -head[2]))
+	local := parent.Inv().Mul4(targetGlobal[i])
+	transforms[i] = Mat4ToTransform(bone.InverseBindPose.Mul4(local).Mul4(bone.BindPose))
+}
+
+func (r *Retargeter) sampleTimes(animation *Animation) []float32 {
+	times := []float32{0, animation.Duration}
+	if r.SampleRate > 0 {
+		for t := 1 / r.SampleRate; t < animation.Duration; t += 1 / r.SampleRate {
+			times = append(times, t)
+		}
+	} else {
+		for i := range animation.Tracks {
+			track := &animation.Tracks[i]
+			for _, channel := range [3]*Channel{&track.Translate, &track.Rotation, &track.Scale} {
+				for _, t := range channel.Times {
+					if t > 0 && t < animation.Duration {
+						times = append(times, t)
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	unique := times[:1]
+	for _, t := range times[1:] {
+		if t != unique[len(unique)-1] {
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,94 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func boundBone(name string, parent, index int, bindPose mgl32.Mat4) Bone {
+	return Bone{Name: name, BindPose: bindPose, InverseBindPose: bindPose.Inv(), ParentIndex: parent, Index: index}
+}
+
+// retargetSkeletons returns a three bone source standing one unit per bone,
+// and a target twice its size without the middle bone and with other joint axes
+func retargetSkeletons() (*Skeleton, *Skeleton) {
+	source := &Skeleton{Bones: []Bone{
+		boundBone("hips", -1, 0, mgl32.Translate3D(0, 1, 0)),
+		boundBone("spine", 0, 1, mgl32.Translate3D(0, 2, 0)),
+		boundBone("head", 1, 2, mgl32.Translate3D(0, 3, 0)),
+	}}
+	target := &Skeleton{Bones: []Bone{
+		boundBone("pelvis", -1, 0, mgl32.Translate3D(0, 2, 0).Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(90)))),
+		boundBone("head", 0, 1, mgl32.Translate3D(0, 4, 0).Mul4(mgl32.HomogRotate3DX(mgl32.DegToRad(90)))),
+	}}
+	return source, target
+}
+
+func TestRetarget(t *testing.T) {
+	source, target := retargetSkeletons()
+	bend := Keyframe{Transforms: []Transform{
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{1, 0, 0}, [3]float32{}),
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 90}),
+		IdentityTransform(),
+	}, SampleTime: 1}
+	rest := Keyframe{Transforms: []Transform{IdentityTransform(), IdentityTransform(), IdentityTransform()}}
+	clip := Animation{Name: "bend", Duration: 1, Keyframes: []Keyframe{rest, bend}}
+
+	retargeter := NewRetargeter(source, target)
+	if retargeter.BoneMap[0] != 0 || retargeter.BoneMap[1] != 2 {
+		t.Fatalf("expected the root and the head to be mapped, got %v", retargeter.BoneMap)
+	}
+	retargeted, err := retargeter.Retarget(clip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator, err := NewAnimator(target, []Animation{retargeted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetLooping(0, false)
+	animator.SetBlendTree(&ClipNode{Animation: 0})
+	animator.Update(1)
+	//The root moves twice as far, the head turns a quarter around z about its own joint
+	root := animator.GlobalPoseMatrices[0].Mul4x1(mgl32.Vec4{0, 2, 0, 1}).Vec3()
+	top := animator.GlobalPoseMatrices[1].Mul4x1(mgl32.Vec4{0, 5, 0, 1}).Vec3()
+	if !root.ApproxEqualThreshold(mgl32.Vec3{2, 2, 0}, 1e-5) || !top.ApproxEqualThreshold(mgl32.Vec3{1, 4, 0}, 1e-5) {
+		t.Errorf("expected the root at (2, 2, 0) and the head's tip at (1, 4, 0), got %v and %v", root, top)
+	}
+
+	//Measured from a bent reference the same pose is the target's bind pose
+	retargeter.SourceReference = &bend
+	retargeted, err = retargeter.Retarget(clip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := retargeted.Keyframes[len(retargeted.Keyframes)-1]
+	for i, transform := range last.Transforms {
+		if !transform.Rotation.OrientationEqualThreshold(mgl32.QuatIdent(), 1e-5) || mgl32.Vec3(transform.Translate).Len() > 1e-5 {
+			t.Errorf("bone %v: expected the bind pose, got %v", i, transform)
+		}
+	}
+}
+
+func TestRetargetBoneTable(t *testing.T) {
+	source, target := retargetSkeletons()
+	retargeter := NewRetargeter(source, target)
+	if err := retargeter.MapBones(map[string]string{"head": "spine"}); err != nil {
+		t.Fatal(err)
+	}
+	if retargeter.BoneMap[1] != 1 {
+		t.Errorf("expected the head to follow the spine, got %v", retargeter.BoneMap)
+	}
+	if err := retargeter.MapBones(map[string]string{"tail": "spine"}); err == nil {
+		t.Errorf("expected an error for an unknown bone")
+	}
+	retargeter.SampleRate = 4
+	retargeted, err := retargeter.Retarget(testClip("walk", 3, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retargeted.Keyframes) != 5 || retargeted.Keyframes[2].SampleTime != 0.5 {
+		t.Errorf("expected five keyframes a quarter second apart, got %v", len(retargeted.Keyframes))
+	}
+}
@@ -0,0 +1,70 @@
+package anim
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Rig holds the data animators only read: a skeleton and its clips. Animators
+// made by the same rig share it, so neither may be changed once the rig
+// exists. Bone masks are part of the skeleton and have to be added before
+// NewRig, the Add*Mask methods of a shared skeleton return an error
+type Rig struct {
+	Skeleton   *Skeleton
+	Animations []Animation
+	order      []int
+}
+
+// NewRig checks the clips against the skeleton and converts their dense
+// keyframes into tracks
+func NewRig(skeleton *Skeleton, animations []Animation) (*Rig, error) {
+	//Error testing
+	boneCount := len(skeleton.Bones)
+	animations = append([]Animation(nil), animations...)
+	for a := range animations {
+		for b := range animations[a].Keyframes {
+			if len(animations[a].Keyframes[b].Transforms) != boneCount {
+				return nil, fmt.Errorf("anim: Incompatible keyframe %v of animation %v, expected %v, but go %v transforms", b, a, boneCount, len(animations[a].Keyframes[b].Transforms))
+			}
+		}
+		if len(animations[a].Tracks) == 0 {
+			animations[a].BuildTracks()
+		}
+		if err := animations[a].validateTracks(boneCount); err != nil {
+			return nil, fmt.Errorf("anim: animation %v: %v", a, err)
+		}
+	}
+	order, err := skeleton.ParentFirstOrder()
+	if err != nil {
+		return nil, err
+	}
+	//Only written once, so rigs made later do not race with running animators
+	if !skeleton.shared {
+		skeleton.shared = true
+	}
+	return &Rig{Skeleton: skeleton, Animations: animations, order: order}, nil
+}
+
+// NewAnimator returns an animator playing the rig's clips, with its own clocks,
+// parameters and poses
+func (r *Rig) NewAnimator() *Animator {
+	boneCount := len(r.Skeleton.Bones)
+	a := Animator{skeleton: r.Skeleton, animations: r.Animations, order: r.order}
+	a.baseClock, a.timeScale = newClock(), 1
+	a.clock = a.baseClock
+	a.animationStates = make([]animationState, len(r.Animations))
+	for i := range a.animationStates {
+		a.animationStates[i].clock = a.baseClock
+		a.animationStates[i].playbackRate = 1
+		a.animationStates[i].sampledFrame = -1
+	}
+	a.parameters = make(map[string]float32)
+	a.triggers = make(map[string]bool)
+	a.stateMachines = make(map[*StateMachine]*stateMachineState)
+	a.syncGroups = make(map[string]*syncGroup)
+	a.localPose = newPose(boneCount)
+	a.GlobalPoseMatrices = make([]mgl32.Mat4, boneCount)
+	a.GlobalDualQuaternions = make([]DualQuat, boneCount)
+	return &a
+}
@@ -0,0 +1,101 @@
+package anim
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RootMotion is the horizontal displacement and turn around the vertical axis
+// of the root bone over the last Update. Translation is in model space, as
+// seen from the root's facing at the start of the frame
+type RootMotion struct {
+	Translation mgl32.Vec3
+	Yaw         float32
+}
+
+// SetRootMotion makes Update extract the root's horizontal motion and yaw from
+// the clips it plays, removing them from the pose
+func (a *Animator) SetRootMotion(enabled bool) {
+	a.rootMotionEnabled = enabled
+}
+
+// RootMotion returns the motion extracted by the last Update
+func (a *Animator) RootMotion() RootMotion {
+	return a.rootMotion
+}
+
+// accumulateRootMotion adds the root's motion between two unwrapped clip
+// times, scaled by the clip's weight. Ping pong clips walk back along the path
+// they came, so only repeating ones add up whole cycles
+func (a *Animator) accumulateRootMotion(animationIndex int, previous, current float32, mode LoopMode, weight float32) {
+	if !a.rootMotionEnabled || a.evaluatingLayers || weight <= 0 {
+		return
+	}
+	animation := &a.animations[animationIndex]
+	startPosition, startYaw := a.rootPose(animation, mode.wrap(previous, animation.Duration))
+	endPosition, endYaw := a.rootPose(animation, mode.wrap(current, animation.Duration))
+	translation, yaw := endPosition.Sub(startPosition), wrapAngle(endYaw-startYaw)
+	if mode == LoopRepeat && animation.Duration > 0 {
+		//Every wrap adds the motion of a whole cycle
+		if wraps := math.Floor(float64(current/animation.Duration)) - math.Floor(float64(previous/animation.Duration)); wraps != 0 {
+			firstPosition, firstYaw := a.rootPose(animation, 0)
+			lastPosition, lastYaw := a.rootPose(animation, animation.Duration)
+			translation = translation.Add(lastPosition.Sub(firstPosition).Mul(float32(wraps)))
+			yaw += float32(wraps) * wrapAngle(lastYaw-firstYaw)
+		}
+	}
+	translation = mgl32.Rotate3DY(-startYaw).Mul3x1(translation)
+	a.rootMotion.Translation = a.rootMotion.Translation.Add(mgl32.Vec3{translation[0], 0, translation[2]}.Mul(weight))
+	a.rootMotion.Yaw += yaw * weight
+}
+
+// rootPose returns the model space position of the root bone's head and its
+// yaw, at time t of an animation
+func (a *Animator) rootPose(animation *Animation, t float32) (mgl32.Vec3, float32) {
+	root := &a.skeleton.Bones[a.skeleton.RootIndex]
+	transform := IdentityTransform()
+	for i := range animation.Tracks {
+		if animation.Tracks[i].BoneIndex == a.skeleton.RootIndex {
+			animation.Tracks[i].sample(t, &transform)
+		}
+	}
+	return modelSpaceRoot(root, root.BindPose.Mul4(TransformToMat4(transform)).Mul4(root.InverseBindPose))
+}
+
+func modelSpaceRoot(root *Bone, m mgl32.Mat4) (mgl32.Vec3, float32) {
+	position := m.Mul4x1(root.BindPose.Col(3)).Vec3()
+	forward := m.Mul4x1(mgl32.Vec4{0, 0, 1, 0})
+	return position, float32(math.Atan2(float64(forward[0]), float64(forward[2])))
+}
+
+// removeRootMotion keeps the root's head above its bind position, facing
+// along the model's forward axis
+func (a *Animator) removeRootMotion(transform *Transform) {
+	root := &a.skeleton.Bones[a.skeleton.RootIndex]
+	m := root.BindPose.Mul4(TransformToMat4(*transform)).Mul4(root.InverseBindPose)
+	position, yaw := modelSpaceRoot(root, m)
+	head := root.BindPose.Col(3)
+	target := mgl32.Vec3{head[0], position[1], head[2]}
+	m = mgl32.Translate3D(target[0], target[1], target[2]).Mul4(mgl32.HomogRotate3DY(-yaw)).Mul4(mgl32.Translate3D(-position[0], -position[1], -position[2])).Mul4(m)
+	*transform = Mat4ToTransform(root.InverseBindPose.Mul4(m).Mul4(root.BindPose))
+}
+
+func wrapTime(t, duration float32, loop bool) float32 {
+	if loop && duration > 0 {
+		return t - duration*float32(math.Floor(float64(t/duration)))
+	} else if t > duration {
+		return duration
+	}
+	return t
+}
+
+func wrapAngle(angle float32) float32 {
+	for angle > math.Pi {
+		angle -= 2 * math.Pi
+	}
+	for angle < -math.Pi {
+		angle += 2 * math.Pi
+	}
+	return angle
+}
@@ -0,0 +1,56 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestRootMotion(t *testing.T) {
+	//Walks 2 units forward every second while bobbing up by 1 halfway through
+	walk := Animation{Name: "walk", Duration: 1, Tracks: []Track{NewTrack(0, []float32{0, 0.5, 1}, []Transform{
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0, 0}, [3]float32{}),
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 1, 1}, [3]float32{}),
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0, 2}, [3]float32{}),
+	})}}
+	//Turns 90 degrees left while stepping 1 unit forward
+	turn := Animation{Name: "turn", Duration: 1, Tracks: []Track{NewTrack(0, []float32{0, 1}, []Transform{
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0, 0}, [3]float32{}),
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0, 1}, [3]float32{0, 90, 0}),
+	})}}
+	animator, err := NewAnimator(testSkeleton(1), []Animation{walk, turn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetRootMotion(true)
+	walkNode := &ClipNode{Animation: 0}
+	animator.SetBlendTree(walkNode)
+
+	animator.Update(0.5)
+	if motion := animator.RootMotion(); !motion.Translation.ApproxEqual(mgl32.Vec3{0, 0, 1}) || motion.Yaw != 0 {
+		t.Errorf("expected to move 1 forward, got %+v", motion)
+	}
+	//The vertical bob stays in the pose, the forward motion is removed
+	if position := animator.GlobalPoseMatrices[0].Col(3).Vec3(); !position.ApproxEqual(mgl32.Vec3{0, 1, 0}) {
+		t.Errorf("expected the root to stay in place, got %v", position)
+	}
+	//Wrapping around the loop keeps accumulating
+	animator.Update(1.25)
+	if motion := animator.RootMotion(); !motion.Translation.ApproxEqualThreshold(mgl32.Vec3{0, 0, 2.5}, 1e-5) {
+		t.Errorf("expected to move 2.5 forward across the loop, got %+v", motion)
+	}
+
+	animator.SetBlendTree(&LerpNode{A: walkNode, B: &ClipNode{Animation: 1}, Weight: 0.5})
+	animator.Play(1)
+	animator.Update(0.5)
+	motion := animator.RootMotion()
+	if !mgl32.FloatEqualThreshold(motion.Yaw, mgl32.DegToRad(22.5), 1e-5) {
+		t.Errorf("expected half of a 45 degree turn, got %v", mgl32.RadToDeg(motion.Yaw))
+	}
+	if !mgl32.FloatEqualThreshold(motion.Translation[2], 0.5+0.25, 1e-5) {
+		t.Errorf("expected the blended forward motion, got %v", motion.Translation)
+	}
+	if _, yaw := modelSpaceRoot(&animator.skeleton.Bones[0], animator.GlobalPoseMatrices[0]); !mgl32.FloatEqualThreshold(yaw, 0, 1e-5) {
+		t.Errorf("expected the yaw to be removed from the pose, got %v", yaw)
+	}
+}
@@ -0,0 +1,225 @@
+package anim
+
+// AnyState as the From of a Transition lets it leave every other state
+const AnyState = -1
+
+// StateMachine is a blend node that plays one of its states at a time and
+// crossfades between them. The machine only describes states and transitions,
+// its progress is kept by each Animator so a machine can be shared
+type StateMachine struct {
+	States      []State
+	Transitions []Transition
+	Entry       int
+}
+
+// State plays Node, usually a ClipNode or a blend tree, whose clips are
+// restarted every time the state is entered
+type State struct {
+	Name string
+	Node BlendNode
+}
+
+// Transition fires from the From state once it has been active for ExitTime
+// seconds, all of its Conditions hold and its Trigger, if any, was set. Its
+// crossfade can only be cut short by another transition when Interruptible
+type Transition struct {
+	From, To      int
+	Conditions    []Condition
+	Trigger       string
+	Duration      float32
+	Easing        Easing
+	ExitTime      float32
+	Interruptible bool
+}
+
+// Condition compares an animator parameter against Value
+type Condition struct {
+	Parameter  string
+	Comparison Comparison
+	Value      float32
+}
+
+type Comparison int
+
+const (
+	Greater Comparison = iota
+	Less
+	Equal
+	NotEqual
+)
+
+// Easing shapes the crossfade weight of a transition over its duration
+type Easing int
+
+const (
+	EaseLinear Easing = iota
+	EaseIn
+	EaseOut
+	EaseInOut
+)
+
+type stateMachineState struct {
+	current         int
+	previous        int
+	stateStart      float32
+	transition      *Transition
+	transitionStart float32
+	//Pose a transition was interrupted at, used as previous state of the next one
+	frozen pose
+}
+
+const frozenState = -1
+
+func (m *StateMachine) evaluate(a *Animator, slot int, weight float32) {
+	s := a.stateMachineState(m)
+	s.finishTransition(a.clock.time)
+	if transition := m.nextTransition(a, s); transition != nil {
+		if s.transition != nil {
+			//Zero weight as the clips are evaluated again below
+			m.blend(a, s, slot, 0)
+			if s.frozen.translations == nil {
+				s.frozen = newPose(len(a.skeleton.Bones))
+			}
+			s.frozen.copyFrom(&a.workingPoses[slot])
+			s.previous = frozenState
+		} else {
+			s.previous = s.current
+		}
+		s.current = transition.To
+		s.stateStart = a.clock.time
+		s.transition = transition
+		s.transitionStart = a.clock.time
+		m.States[s.current].Node.restart(a)
+	}
+	m.blend(a, s, slot, weight)
+}
+
+func (m *StateMachine) blend(a *Animator, s *stateMachineState, slot int, weight float32) {
+	s.finishTransition(a.clock.time)
+	if s.transition == nil {
+		m.States[s.current].Node.evaluate(a, slot, weight)
+		return
+	}
+	t := s.transition.Easing.apply((a.clock.time - s.transitionStart) / s.transition.Duration)
+	if s.previous == frozenState {
+		a.workingPoses[slot].copyFrom(&s.frozen)
+	} else {
+		m.States[s.previous].Node.evaluate(a, slot, weight*(1-t))
+	}
+	m.States[s.current].Node.evaluate(a, slot+1, weight*t)
+	a.linearBlend(slot, slot+1, t, slot)
+}
+
+func (s *stateMachineState) finishTransition(globalTime float32) {
+	if s.transition != nil && globalTime-s.transitionStart >= s.transition.Duration {
+		s.transition = nil
+	}
+}
+
+func (m *StateMachine) nextTransition(a *Animator, s *stateMachineState) *Transition {
+	if s.transition != nil && !s.transition.Interruptible {
+		return nil
+	}
+	for i := range m.Transitions {
+		transition := &m.Transitions[i]
+		if transition == s.transition {
+			continue
+		}
+		if transition.From != s.current && (transition.From != AnyState || transition.To == s.current) {
+			continue
+		}
+		if a.clock.time-s.stateStart < transition.ExitTime || !a.conditionsHold(transition.Conditions) {
+			continue
+		}
+		if transition.Trigger != "" {
+			if !a.triggers[transition.Trigger] {
+				continue
+			}
+			delete(a.triggers, transition.Trigger)
+		}
+		return transition
+	}
+	return nil
+}
+
+func (m *StateMachine) restart(a *Animator) {
+	delete(a.stateMachines, m)
+}
+
+func (m *StateMachine) poseCount() int {
+	count := 0
+	for i := range m.States {
+		count = maxInt(count, m.States[i].Node.poseCount())
+	}
+	return 1 + count
+}
+
+func (a *Animator) stateMachineState(m *StateMachine) *stateMachineState {
+	s, present := a.stateMachines[m]
+	if !present {
+		s = &stateMachineState{current: m.Entry, stateStart: a.clock.time}
+		a.stateMachines[m] = s
+		m.States[m.Entry].Node.restart(a)
+	}
+	return s
+}
+
+func (a *Animator) conditionsHold(conditions []Condition) bool {
+	for _, condition := range conditions {
+		value := a.parameters[condition.Parameter]
+		switch condition.Comparison {
+		case Greater:
+			if !(value > condition.Value) {
+				return false
+			}
+		case Less:
+			if !(value < condition.Value) {
+				return false
+			}
+		case Equal:
+			if value != condition.Value {
+				return false
+			}
+		case NotEqual:
+			if value == condition.Value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Trigger sets a one shot flag for the next Update, it is consumed by the
+// first transition that fires on it and dropped at the end of the Update when
+// no transition does
+func (a *Animator) Trigger(name string) {
+	a.triggers[name] = true
+}
+
+func (a *Animator) clearTriggers() {
+	for name := range a.triggers {
+		delete(a.triggers, name)
+	}
+}
+
+// CurrentState returns the name of the state the machine is in, or is
+// transitioning into
+func (a *Animator) CurrentState(m *StateMachine) string {
+	if s, present := a.stateMachines[m]; present {
+		return m.States[s.current].Name
+	}
+	return m.States[m.Entry].Name
+}
+
+func (e Easing) apply(t float32) float32 {
+	t = clamp(0, t, 1)
+	switch e {
+	case EaseIn:
+		return t * t
+	case EaseOut:
+		return t * (2 - t)
+	case EaseInOut:
+		return t * t * (3 - 2*t)
+	}
+	return t
+}
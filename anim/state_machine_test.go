@@ -0,0 +1,128 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestStateMachineCrossfade(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(1), []Animation{testClip("idle", 1, 0), testClip("jump", 1, 4), testClip("land", 1, 8)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	machine := &StateMachine{
+		States: []State{{Name: "idle", Node: &ClipNode{Animation: 0}}, {Name: "jump", Node: &ClipNode{Animation: 1}}, {Name: "land", Node: &ClipNode{Animation: 2}}},
+		Transitions: []Transition{
+			{From: 0, To: 1, Conditions: []Condition{{Parameter: "airborne", Comparison: Greater, Value: 0.5}}, Duration: 1},
+			{From: 1, To: 2, Trigger: "land", Duration: 1, ExitTime: 0.25},
+			{From: AnyState, To: 0, Conditions: []Condition{{Parameter: "reset", Comparison: Equal, Value: 1}}},
+		}}
+	animator.SetBlendTree(machine)
+	x := func() float32 {
+		return animator.GlobalPoseMatrices[0].Col(3).X()
+	}
+
+	animator.Update(0.5)
+	if state := animator.CurrentState(machine); state != "idle" || x() != 0 {
+		t.Errorf("expected to start idle at 0, got %v at %v", state, x())
+	}
+	animator.SetParameter("airborne", 1)
+	animator.Update(0.5)
+	if state, start := animator.CurrentState(machine), animator.animationStates[1].clockTime; state != "jump" || start != 1 {
+		t.Errorf("expected to enter jump at 1, got %v at %v", state, start)
+	}
+	//The crossfade is not interruptible, so the trigger is dropped unused
+	animator.Trigger("land")
+	animator.Update(0.5)
+	if !mgl32.FloatEqual(x(), 2) {
+		t.Errorf("expected to be halfway into jump, got %v", x())
+	}
+	if animator.triggers["land"] {
+		t.Errorf("expected the unused trigger to be dropped")
+	}
+	animator.Trigger("land")
+	animator.Update(0.5)
+	if state := animator.CurrentState(machine); state != "land" || x() != 4 {
+		t.Errorf("expected to start landing from the jump pose, got %v at %v", state, x())
+	}
+	if animator.triggers["land"] {
+		t.Errorf("expected the trigger to be consumed")
+	}
+	animator.Update(0.25)
+	if !mgl32.FloatEqual(x(), 5) {
+		t.Errorf("expected a quarter of the landing crossfade, got %v", x())
+	}
+	animator.SetParameter("reset", 1)
+	animator.Update(0.25)
+	if state := animator.CurrentState(machine); state != "land" {
+		t.Errorf("expected the crossfade to block the reset, got %v", state)
+	}
+	animator.Update(0.5)
+	if state := animator.CurrentState(machine); state != "idle" || x() != 0 {
+		t.Errorf("expected an immediate reset to idle, got %v at %v", state, x())
+	}
+}
+
+func TestStateMachineStaleTrigger(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(1), []Animation{testClip("idle", 1, 0), testClip("jump", 1, 4)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	machine := &StateMachine{
+		States: []State{{Name: "idle", Node: &ClipNode{Animation: 0}}, {Name: "jump", Node: &ClipNode{Animation: 1}}},
+		Transitions: []Transition{
+			{From: 0, To: 1, Conditions: []Condition{{Parameter: "airborne", Comparison: Greater, Value: 0.5}}},
+			{From: 1, To: 0, Trigger: "land"},
+		}}
+	animator.SetBlendTree(machine)
+	//No transition leaves idle on the trigger, it must not wait for jump
+	animator.Trigger("land")
+	animator.Update(0.5)
+	if len(animator.triggers) != 0 {
+		t.Errorf("expected the unused trigger to be dropped, got %v", animator.triggers)
+	}
+	animator.SetParameter("airborne", 1)
+	animator.Update(0.5)
+	animator.Update(0.5)
+	if state := animator.CurrentState(machine); state != "jump" {
+		t.Errorf("expected a stale trigger to leave jump alone, got %v", state)
+	}
+	animator.Trigger("land")
+	animator.Update(0.5)
+	if state := animator.CurrentState(machine); state != "idle" {
+		t.Errorf("expected a fresh trigger to land, got %v", state)
+	}
+}
+
+func TestStateMachineInterrupt(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(1), []Animation{testClip("a", 1, 0), testClip("b", 1, 4), testClip("c", 1, 8)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := ParseBlendTree([]byte(`{"type": "state_machine", "entry": "a",
+		"states": [{"name": "a", "node": {"type": "clip", "clip": "a"}}, {"name": "b", "node": {"type": "clip", "clip": "b"}}, {"name": "c", "node": {"type": "clip", "clip": "c"}}],
+		"transitions": [
+			{"from": "a", "to": "b", "trigger": "go", "duration": 1, "easing": "in_out", "interruptible": true},
+			{"from": "b", "to": "c", "conditions": [{"parameter": "hurry", "comparison": ">", "value": 0}], "duration": 1}]}`), animator.skeleton, animator.animations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(root)
+	animator.Trigger("go")
+	animator.Update(0)
+	animator.Update(0.5)
+	if !mgl32.FloatEqual(animator.GlobalPoseMatrices[0].Col(3).X(), 2) {
+		t.Errorf("expected to be halfway from a to b, got %v", animator.GlobalPoseMatrices[0].Col(3).X())
+	}
+	//Interrupting freezes the pose reached so far and fades from it
+	animator.SetParameter("hurry", 1)
+	animator.Update(0)
+	animator.Update(0.5)
+	if x := animator.GlobalPoseMatrices[0].Col(3).X(); !mgl32.FloatEqual(x, 5) {
+		t.Errorf("expected to be halfway from the frozen pose to c, got %v", x)
+	}
+	if _, err := ParseBlendTree([]byte(`{"type": "state_machine", "entry": "a", "states": [{"name": "a", "node": {"type": "clip", "clip": "a"}}], "transitions": [{"from": "a", "to": "b"}]}`), animator.skeleton, animator.animations); err == nil {
+		t.Errorf("expected an error for a transition to an unknown state")
+	}
+}
@@ -0,0 +1,154 @@
+package anim
+
+import "math"
+
+// syncGroup is the shared clock of the clips played by ClipNodes of the same
+// SyncGroup. Phase runs from 0 to 1 over a cycle of the leader, the clip that
+// had the highest weight on the previous frame, at the leader's playback rate.
+// Markers names the events the clips of the group line up by
+type syncGroup struct {
+	leader     int
+	phase      float32
+	frame      int
+	next       int
+	nextWeight float32
+	markers    []string
+}
+
+// SetSyncMarkers makes the clips of a sync group line up by the events with
+// the given names, like "left foot down" and "right foot down", instead of by
+// their normalized time. Each stretch between two markers is scaled to fit,
+// so clips of different lengths put their feet down together. The markers
+// have to appear in the same order in every clip of the group, clips without
+// them fall back to the normalized time
+func (a *Animator) SetSyncMarkers(group string, names ...string) {
+	a.syncGroup(group).markers = names
+}
+
+func (a *Animator) syncGroup(name string) *syncGroup {
+	g, present := a.syncGroups[name]
+	if !present {
+		g = &syncGroup{leader: -1, frame: -1, next: -1}
+		a.syncGroups[name] = g
+	}
+	return g
+}
+
+// advanceSyncGroup moves the group's phase once per frame, before its first
+// clip is sampled. A group that was not played on the previous frame starts
+// over with the clip sampling it
+func (a *Animator) advanceSyncGroup(g *syncGroup, animationIndex int) {
+	if g.frame == a.frame {
+		return
+	}
+	if g.frame != a.frame-1 || g.leader < 0 {
+		g.leader, g.phase = animationIndex, 0
+	} else {
+		if g.next >= 0 && g.next != g.leader {
+			//The new leader takes over at the matching point of its own cycle
+			previous, next := &a.animations[g.leader], &a.animations[g.next]
+			if next.Duration > 0 {
+				g.phase = g.syncTime(previous, next, g.phase*previous.Duration) / next.Duration
+			}
+			g.leader = g.next
+		}
+		if leader := &a.animations[g.leader]; leader.Duration > 0 {
+			g.phase += (a.clock.time - a.clock.previous) * a.animationStates[g.leader].playbackRate / leader.Duration
+			g.phase -= float32(math.Floor(float64(g.phase)))
+		}
+	}
+	g.frame, g.next, g.nextWeight = a.frame, -1, 0
+}
+
+// sampleSynced samples a clip at the time matching its sync group's phase.
+// Clips in a group always loop, events and root motion are taken from the
+// clip's own time on the previous frame if it was sampled then
+func (a *Animator) sampleSynced(group string, sampleIndex, resultIndex int, weight float32) {
+	g := a.syncGroup(group)
+	a.advanceSyncGroup(g, sampleIndex)
+	if weight > g.nextWeight {
+		g.next, g.nextWeight = sampleIndex, weight
+	}
+	leader, animation := &a.animations[g.leader], &a.animations[sampleIndex]
+	t := g.syncTime(leader, animation, g.phase*leader.Duration)
+	state := &a.animationStates[sampleIndex]
+	if state.sampledFrame == a.frame-1 {
+		//Unwrap the time in the direction the group plays
+		current := state.sampledTime + wrapTime(t-state.sampledTime, animation.Duration, true)
+		if a.animationStates[g.leader].playbackRate < 0 && current > state.sampledTime {
+			current -= animation.Duration
+		}
+		a.fireEvents(sampleIndex, state.sampledTime, current, LoopRepeat, weight)
+		a.accumulateRootMotion(sampleIndex, state.sampledTime, current, LoopRepeat, weight)
+	}
+	if state.sampledFrame != a.frame {
+		state.sampledFrame, state.sampledTime = a.frame, t
+	}
+	animation.sample(t, &a.workingPoses[resultIndex])
+}
+
+// syncTime maps time t of one clip of the group to the matching time of
+// another, by the markers both clips have or else by normalized time
+func (g *syncGroup) syncTime(from, to *Animation, t float32) float32 {
+	if from == to {
+		return t
+	}
+	if start, end, ok := g.markerSpan(from, t); ok {
+		fraction := float32(0)
+		if end.Time > start.Time {
+			fraction = (t - start.Time) / (end.Time - start.Time)
+		}
+		for _, event := range to.Events {
+			if event.Name == start.Name {
+				_, next, _ := g.markerSpan(to, event.Time)
+				return wrapTime(event.Time+fraction*(next.Time-event.Time), to.Duration, true)
+			}
+		}
+	}
+	if from.Duration <= 0 {
+		return 0
+	}
+	return t / from.Duration * to.Duration
+}
+
+// markerSpan finds the markers before and after time t of a clip, continuing
+// into the previous or next cycle where t is before the first or after the
+// last one
+func (g *syncGroup) markerSpan(animation *Animation, t float32) (start, end Event, ok bool) {
+	var first, last Event
+	foundStart, foundEnd := false, false
+	for _, event := range animation.Events {
+		if !g.isMarker(event.Name) {
+			continue
+		}
+		if !ok || event.Time < first.Time {
+			first = event
+		}
+		if !ok || event.Time > last.Time {
+			last = event
+		}
+		ok = true
+		if event.Time <= t && (!foundStart || event.Time > start.Time) {
+			start, foundStart = event, true
+		}
+		if event.Time > t && (!foundEnd || event.Time < end.Time) {
+			end, foundEnd = event, true
+		}
+	}
+	if !foundStart {
+		start = Event{Name: last.Name, Time: last.Time - animation.Duration}
+	}
+	if !foundEnd {
+		end = Event{Name: first.Name, Time: first.Time + animation.Duration}
+	}
+	return start, end, ok
+}
+
+func (g *syncGroup) isMarker(name string) bool {
+	for _, marker := range g.markers {
+		if marker == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// clockClip moves the root along x by one unit a second, so its x is the
+// clip's time
+func clockClip(name string, duration float32, events ...Event) Animation {
+	start := Keyframe{Transforms: []Transform{IdentityTransform()}}
+	end := Keyframe{Transforms: []Transform{TransformFromEuler([3]float32{1, 1, 1}, [3]float32{duration, 0, 0}, [3]float32{})}, SampleTime: duration}
+	return Animation{Name: name, Duration: duration, Keyframes: []Keyframe{start, end}, Events: events}
+}
+
+func TestSyncGroupPhase(t *testing.T) {
+	animator, err := NewAnimator(testSkeleton(1), []Animation{clockClip("walk", 1), clockClip("run", 0.5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(&LerpNode{A: &ClipNode{Animation: 0, SyncGroup: "move"}, B: &ClipNode{Animation: 1, SyncGroup: "move"}, Parameter: "speed"})
+	animator.SetPlaybackRate(1, 3)
+	animator.SetParameter("speed", 0.25)
+	expect := func(walk, run float32) {
+		t.Helper()
+		for i, expected := range []float32{walk, run} {
+			if result := animator.animationStates[i].sampledTime; mgl32.Abs(result-expected) > 1e-5 {
+				t.Errorf("clip %v: expected time %v, got %v", i, expected, result)
+			}
+		}
+	}
+	animator.Update(0.25)
+	expect(0, 0)
+	//The walk leads, the run follows at the same phase whatever its own rate
+	animator.Update(0.25)
+	expect(0.25, 0.125)
+	animator.SetParameter("speed", 0.75)
+	animator.Update(0.1)
+	expect(0.35, 0.175)
+	//Once the run has the higher weight its duration and rate set the pace
+	animator.Update(0.1)
+	expect(0.95, 0.475)
+	if x := animator.GlobalPoseMatrices[0].Col(3).X(); mgl32.Abs(x-(0.25*0.95+0.75*0.475)) > 1e-5 {
+		t.Errorf("expected the blend of both clips, got %v", x)
+	}
+}
+
+func TestSyncMarkers(t *testing.T) {
+	walk := clockClip("walk", 1, Event{Name: "left", Time: 0.1}, Event{Name: "right", Time: 0.6})
+	run := clockClip("run", 0.5, Event{Name: "right", Time: 0.05}, Event{Name: "left", Time: 0.3}, Event{Name: "breath", Time: 0.4})
+	g := &syncGroup{markers: []string{"left", "right"}}
+	for _, c := range []struct{ walk, run float32 }{{0.1, 0.3}, {0.35, 0.425}, {0.6, 0.05}, {0.8, 0.15}, {0.05, 0.275}} {
+		if result := g.syncTime(&walk, &run, c.walk); mgl32.Abs(result-c.run) > 1e-5 {
+			t.Errorf("walk at %v: expected the run at %v, got %v", c.walk, c.run, result)
+		}
+	}
+	//Without markers the clips line up by normalized time
+	if result := (&syncGroup{}).syncTime(&walk, &run, 0.8); mgl32.Abs(result-0.4) > 1e-5 {
+		t.Errorf("expected the run at 0.4, got %v", result)
+	}
+
+	//Both clips reach each foot contact on the same frame
+	animator, err := NewAnimator(testSkeleton(1), []Animation{walk, run})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(&LerpNode{A: &ClipNode{Animation: 0, SyncGroup: "move"}, B: &ClipNode{Animation: 1, SyncGroup: "move"}, Weight: 0.5})
+	animator.SetSyncMarkers("move", "left", "right")
+	contacts := 0
+	for frame := 0; frame < 100; frame++ {
+		animator.Update(0.03)
+		fired := map[string]int{}
+		for _, event := range animator.Events() {
+			if event.Name != "breath" {
+				fired[event.Name] += 1 << uint(event.Animation)
+			}
+		}
+		for name, clips := range fired {
+			if clips != 3 {
+				t.Fatalf("frame %v: expected %v from both clips, got %v", frame, name, animator.Events())
+			}
+			contacts++
+		}
+	}
+	if contacts != 6 {
+		t.Errorf("expected 6 foot contacts in 3 seconds, got %v", contacts)
+	}
+}
@@ -0,0 +1,266 @@
+package anim
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	translateWidth = 3
+	rotationWidth  = 4
+	scaleWidth     = 3
+)
+
+// Track animates a single bone, each of its channels keeps its own key times.
+// A channel without keys leaves that part of the bone in its bind pose
+type Track struct {
+	BoneIndex int
+	Translate Channel
+	Rotation  Channel
+	Scale     Channel
+}
+
+// Channel values are stored flat, three floats per key for translation and
+// scale, four (x, y, z, w) for rotation quaternions. Hermite tangents hold one
+// slope per value and key, Bezier tangents a (time, value) control point pair
+type Channel struct {
+	Times         []float32
+	Values        []float32
+	Interpolation Interpolation
+	InTangents    []float32
+	OutTangents   []float32
+}
+
+// Tangents of a curve track given per key as transforms, Hermite tangents are
+// slopes per second while Bezier tangents are control points placed at
+// InTimes and OutTimes
+type Tangents struct {
+	In, Out           []Transform
+	InTimes, OutTimes []float32
+}
+
+// NewTrack splits a sequence of bone transforms into per channel keys, channels
+// that never change are collapsed into a single key
+func NewTrack(boneIndex int, times []float32, transforms []Transform) Track {
+	return NewCurveTrack(boneIndex, times, transforms, InterpolationLinear, nil)
+}
+
+// NewCurveTrack is NewTrack for channels that use the given interpolation,
+// tangents may be nil for step, linear and Catmull-Rom curves
+func NewCurveTrack(boneIndex int, times []float32, transforms []Transform, interpolation Interpolation, tangents *Tangents) Track {
+	track := Track{BoneIndex: boneIndex}
+	var values, in, out [3][]float32
+	var previous mgl32.Quat
+	for k := range transforms {
+		//Keep neighbouring keys in the same hemisphere
+		sign := float32(1)
+		if k > 0 && previous.Dot(transforms[k].Rotation) < 0 {
+			sign = -1
+		}
+		previous = transforms[k].Rotation.Scale(sign)
+		values = appendTransform(values, transforms[k], sign)
+		if tangents == nil {
+			continue
+		}
+		switch interpolation {
+		case InterpolationHermite:
+			in = appendTransform(in, tangents.In[k], sign)
+			out = appendTransform(out, tangents.Out[k], sign)
+		case InterpolationBezier:
+			//Control points follow the hemisphere of their own key
+			inControl, outControl := tangents.In[k], tangents.Out[k]
+			inSign, outSign := float32(1), float32(1)
+			if inControl.Rotation.Dot(previous) < 0 {
+				inSign = -1
+			}
+			if outControl.Rotation.Dot(previous) < 0 {
+				outSign = -1
+			}
+			in = appendControlPoint(in, tangents.InTimes[k], inControl, inSign)
+			out = appendControlPoint(out, tangents.OutTimes[k], outControl, outSign)
+		}
+	}
+	track.Translate = newChannel(times, values[0], in[0], out[0], translateWidth, interpolation)
+	track.Rotation = newChannel(times, values[1], in[1], out[1], rotationWidth, interpolation)
+	track.Scale = newChannel(times, values[2], in[2], out[2], scaleWidth, interpolation)
+	return track
+}
+
+func appendTransform(values [3][]float32, t Transform, rotationSign float32) [3][]float32 {
+	q := t.Rotation.Scale(rotationSign)
+	values[0] = append(values[0], t.Translate[:]...)
+	values[1] = append(values[1], q.V[0], q.V[1], q.V[2], q.W)
+	values[2] = append(values[2], t.Scale[:]...)
+	return values
+}
+
+func appendControlPoint(values [3][]float32, time float32, t Transform, rotationSign float32) [3][]float32 {
+	q := t.Rotation.Scale(rotationSign)
+	for i := 0; i < 3; i++ {
+		values[0] = append(values[0], time, t.Translate[i])
+	}
+	for _, v := range [4]float32{q.V[0], q.V[1], q.V[2], q.W} {
+		values[1] = append(values[1], time, v)
+	}
+	for i := 0; i < 3; i++ {
+		values[2] = append(values[2], time, t.Scale[i])
+	}
+	return values
+}
+
+func newChannel(times, values, in, out []float32, width int, interpolation Interpolation) Channel {
+	if len(times) == 0 {
+		return Channel{}
+	}
+	if !channelIsConstant(values, in, out, width, interpolation) {
+		return Channel{Times: append([]float32(nil), times...), Values: values, Interpolation: interpolation, InTangents: in, OutTangents: out}
+	}
+	return Channel{Times: []float32{times[0]}, Values: values[:width:width], Interpolation: interpolation}
+}
+
+func channelIsConstant(values, in, out []float32, width int, interpolation Interpolation) bool {
+	for i := width; i < len(values); i++ {
+		if values[i] != values[i%width] {
+			return false
+		}
+	}
+	for _, tangents := range [2][]float32{in, out} {
+		for i := range tangents {
+			switch {
+			case interpolation == InterpolationHermite && tangents[i] != 0:
+				return false
+			case interpolation == InterpolationBezier && i%2 == 1 && tangents[i] != values[(i/2)%width]:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isIdentity reports whether every key of the track is the identity transform
+func (track *Track) isIdentity() bool {
+	identities := [3][]float32{{0, 0, 0}, {0, 0, 0, 1}, {1, 1, 1}}
+	for c, channel := range [3]*Channel{&track.Translate, &track.Rotation, &track.Scale} {
+		identity := identities[c]
+		for i, value := range channel.Values {
+			//q and -q are the same rotation
+			if c == 1 && i%rotationWidth == 3 {
+				value = mgl32.Abs(value)
+			}
+			if mgl32.Abs(value-identity[i%len(identity)]) > 1e-5 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// BuildTracks converts the dense Keyframes of an animation into Tracks
+func (a *Animation) BuildTracks() {
+	if len(a.Keyframes) == 0 {
+		return
+	}
+	boneCount := len(a.Keyframes[0].Transforms)
+	times := make([]float32, len(a.Keyframes))
+	for k := range a.Keyframes {
+		times[k] = a.Keyframes[k].SampleTime
+	}
+	transforms := make([]Transform, len(a.Keyframes))
+	a.Tracks = make([]Track, boneCount)
+	for b := 0; b < boneCount; b++ {
+		for k := range a.Keyframes {
+			transforms[k] = a.Keyframes[k].Transforms[b]
+		}
+		a.Tracks[b] = NewTrack(b, times, transforms)
+	}
+}
+
+func (a *Animation) validateTracks(boneCount int) error {
+	for i := range a.Tracks {
+		track := &a.Tracks[i]
+		if track.BoneIndex < 0 || track.BoneIndex >= boneCount {
+			return fmt.Errorf("track %v targets bone %v, but the skeleton has %v bones", i, track.BoneIndex, boneCount)
+		}
+		channels := [3]*Channel{&track.Translate, &track.Rotation, &track.Scale}
+		widths := [3]int{translateWidth, rotationWidth, scaleWidth}
+		for c, channel := range channels {
+			if len(channel.Values) != widths[c]*len(channel.Times) {
+				return fmt.Errorf("track %v has %v values for %v keys", i, len(channel.Values), len(channel.Times))
+			}
+			for k := 1; k < len(channel.Times); k++ {
+				if channel.Times[k] < channel.Times[k-1] {
+					return fmt.Errorf("track %v has unsorted key times", i)
+				}
+			}
+			if err := channel.validateTangents(widths[c]); err != nil {
+				return fmt.Errorf("track %v: %v", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// sample writes the animation at time t into a pose, bones without a track are
+// left in their bind pose
+func (a *Animation) sample(t float32, result *pose) {
+	result.reset()
+	for i := range a.Tracks {
+		bone := a.Tracks[i].BoneIndex
+		a.Tracks[i].sampleInto(t, &result.translations[bone], &result.rotations[bone], &result.scales[bone])
+	}
+}
+
+func (track *Track) sample(t float32, result *Transform) {
+	track.sampleInto(t, (*mgl32.Vec3)(&result.Translate), &result.Rotation, (*mgl32.Vec3)(&result.Scale))
+}
+
+func (track *Track) sampleInto(t float32, translate *mgl32.Vec3, rotation *mgl32.Quat, scale *mgl32.Vec3) {
+	track.Translate.sample(t, translateWidth, translate[:])
+	track.Scale.sample(t, scaleWidth, scale[:])
+	k, alpha, ok := track.Rotation.find(t)
+	if !ok {
+		return
+	}
+	if track.Rotation.Interpolation == InterpolationLinear && alpha != 0 {
+		*rotation = slerpQuat(track.Rotation.quat(k), track.Rotation.quat(k+1), alpha)
+		return
+	}
+	var v [rotationWidth]float32
+	track.Rotation.interpolate(k, alpha, t, rotationWidth, v[:])
+	*rotation = mgl32.Quat{W: v[3], V: mgl32.Vec3{v[0], v[1], v[2]}}.Normalize()
+}
+
+func (c *Channel) sample(t float32, width int, result []float32) {
+	if k, alpha, ok := c.find(t); ok {
+		c.interpolate(k, alpha, t, width, result)
+	}
+}
+
+func (c *Channel) quat(k int) mgl32.Quat {
+	v := c.Values[rotationWidth*k : rotationWidth*(k+1)]
+	return mgl32.Quat{W: v[3], V: mgl32.Vec3{v[0], v[1], v[2]}}
+}
+
+// find returns the key at or before t and how far t is towards the next key
+func (c *Channel) find(t float32) (int, float32, bool) {
+	last := len(c.Times) - 1
+	if last < 0 {
+		return 0, 0, false
+	} else if last == 0 || t <= c.Times[0] {
+		return 0, 0, true
+	} else if t >= c.Times[last] {
+		return last, 0, true
+	}
+	//Binary search so long clips cost O(log n) per channel
+	low, high := 0, last
+	for low < high {
+		mid := (low + high + 1) / 2
+		if c.Times[mid] <= t {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, (t - c.Times[low]) / (c.Times[low+1] - c.Times[low]), true
+}
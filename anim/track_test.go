@@ -0,0 +1,63 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestTracksMatchKeyframeLerp(t *testing.T) {
+	first := Keyframe{SampleTime: 0, Transforms: []Transform{
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 1, 0}, [3]float32{0, 0, -30})}}
+	second := Keyframe{SampleTime: 0.5, Transforms: []Transform{
+		TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+		TransformFromEuler([3]float32{1, 2, 1}, [3]float32{0, 3, 0}, [3]float32{20, 0, 30})}}
+	animation := Animation{Duration: 0.5, Keyframes: []Keyframe{first, second}}
+	animation.BuildTracks()
+	if keys := len(animation.Tracks[0].Rotation.Times); keys != 1 {
+		t.Errorf("expected the constant rotation channel to collapse into 1 key, got %v", keys)
+	}
+
+	sampled := newPose(2)
+	animation.sample(0.2, &sampled)
+	for i := range first.Transforms {
+		var expected Transform
+		lerpTransform(&first.Transforms[i], &second.Transforms[i], 0.4, &expected)
+		if !transformsEqual(sampled.transform(i), expected) {
+			t.Errorf("bone %v: expected %v, got %v", i, expected, sampled.transform(i))
+		}
+	}
+}
+
+func TestSparseChannelsKeepOwnTimes(t *testing.T) {
+	track := Track{
+		BoneIndex: 1,
+		Translate: Channel{Times: []float32{0, 1, 2, 3}, Values: []float32{0, 0, 0, 1, 0, 0, 2, 0, 0, 3, 0, 0}},
+		Rotation:  Channel{Times: []float32{1}, Values: []float32{0, 0, 0, 1}},
+	}
+	animation := Animation{Duration: 3, Tracks: []Track{track}}
+	if err := animation.validateTracks(2); err != nil {
+		t.Fatal(err)
+	}
+	pose := newPose(2)
+	for _, sampleTime := range []float32{-1, 0, 0.25, 1.5, 2, 2.75, 3, 5} {
+		animation.sample(sampleTime, &pose)
+		expected := mgl32.Clamp(sampleTime, 0, 3)
+		if got := pose.translations[1][0]; !mgl32.FloatEqualThreshold(got, expected, 1e-6) {
+			t.Errorf("t=%v: expected translation %v, got %v", sampleTime, expected, got)
+		}
+		if pose.transform(0) != IdentityTransform() || pose.scales[1] != (mgl32.Vec3{1, 1, 1}) {
+			t.Errorf("t=%v: bones without keys should stay in bind pose, got %v", sampleTime, pose)
+		}
+	}
+	if err := animation.validateTracks(1); err == nil {
+		t.Errorf("expected an error for a track outside of the skeleton")
+	}
+}
+
+func transformsEqual(first, second Transform) bool {
+	return mgl32.Vec3(first.Translate).ApproxEqualThreshold(mgl32.Vec3(second.Translate), 1e-5) &&
+		mgl32.Vec3(first.Scale).ApproxEqualThreshold(mgl32.Vec3(second.Scale), 1e-5) &&
+		first.Rotation.OrientationEqualThreshold(second.Rotation, 1e-5)
+}
@@ -6,6 +6,10 @@ type Skeleton struct {
 	Bones           []Bone
 	BindShapeMatrix mgl32.Mat4
 	RootIndex       int
+	//Masks are added before the skeleton is given to NewRig, see AddBoneMask
+	Masks map[string]*BoneMask
+	//shared is set by NewRig, after it the skeleton is read only
+	shared bool
 }
 
 type Bone struct {
@@ -17,27 +21,54 @@ type Bone struct {
 }
 
 type Animator struct {
-	animations         []Animation
-	animationStates    []animationState
-	GlobalPoseMatrices []mgl32.Mat4
-	localPoseMatrices  []mgl32.Mat4
-	workingPoses       []Keyframe
-	localPose          Keyframe
-	globalPosesSet     []bool
-	globalTime         float32
-	skeleton           *Skeleton
+	animations            []Animation
+	animationStates       []animationState
+	GlobalPoseMatrices    []mgl32.Mat4
+	GlobalDualQuaternions []DualQuat
+	workingPoses          []pose
+	blendTree             BlendNode
+	layers                []Layer
+	postProcessors        []PostProcessor
+	parameters            map[string]float32
+	triggers              map[string]bool
+	stateMachines         map[*StateMachine]*stateMachineState
+	syncGroups            map[string]*syncGroup
+	localPose             pose
+	order                 []int
+	clock                 *clock
+	baseClock             *clock
+	layerClocks           []*clock
+	timeScale             float32
+	frame                 int
+	events                []FiredEvent
+	rootMotion            RootMotion
+	rootMotionEnabled     bool
+	evaluatingLayers      bool
+	skeleton              *Skeleton
 }
 
+// Animation clips are sampled from Tracks, dense Keyframes are converted into
+// tracks by NewAnimator
 type Animation struct {
+	Name      string
 	Keyframes []Keyframe
+	Tracks    []Track
+	Events    []Event
 	Duration  float32
 }
 
+// animationState is a clip's own clock, time is its unwrapped time when its
+// clock was at clockTime
 type animationState struct {
 	animationIndex int
-	startTime      float32
+	time           float32
+	clockTime      float32
+	clock          *clock
+	paused         bool
 	playbackRate   float32
-	loop           bool
+	loopMode       LoopMode
+	sampledFrame   int
+	sampledTime    float32
 }
 
 type Keyframe struct {
@@ -48,5 +79,5 @@ type Keyframe struct {
 type Transform struct {
 	Scale     [3]float32
 	Translate [3]float32
-	Rotation  [3]float32
+	Rotation  mgl32.Quat
 }
@@ -0,0 +1,46 @@
+// Command dae2anim converts the clips of a Collada file into an animation file
+// that anim.LoadAnimations reads
+//
+//	dae2anim [-text] model.dae clips.anim
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"training/engine/anim"
+	"training/engine/parse/collada"
+)
+
+func main() {
+	text := flag.Bool("text", false, "write the human readable JSON variant instead of the binary one")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: dae2anim [-text] input.dae output.anim\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	skeleton, animations, err := collada.ParseSkeletonAnimations(flag.Arg(0))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if len(animations) == 0 {
+		log.Fatalf("dae2anim: no animations found in %v\n", flag.Arg(0))
+	}
+	format := anim.ClipBinary
+	if *text {
+		format = anim.ClipText
+	}
+	if err := anim.SaveAnimations(flag.Arg(1), skeleton, animations, format); err != nil {
+		log.Fatalln(err)
+	}
+	for _, animation := range animations {
+		fmt.Printf("%v: %v tracks, %vs\n", animation.Name, len(animation.Tracks), animation.Duration)
+	}
+}
@@ -0,0 +1,130 @@
+package collision
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// AABB is an axis aligned bounding box
+type AABB struct {
+	Min, Max mgl32.Vec3
+}
+
+// Bounds finds the box around a shape from its support points along the axes
+func Bounds(shape Shape) AABB {
+	var box AABB
+	for i := 0; i < 3; i++ {
+		var axis mgl32.Vec3
+		axis[i] = 1
+		box.Max[i] = shape.Support(axis)[i]
+		box.Min[i] = shape.Support(axis.Mul(-1))[i]
+	}
+	return box
+}
+
+func (b AABB) Overlaps(other AABB) bool {
+	for i := 0; i < 3; i++ {
+		if b.Min[i] > other.Max[i] || b.Max[i] < other.Min[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (b AABB) Contains(other AABB) bool {
+	for i := 0; i < 3; i++ {
+		if other.Min[i] < b.Min[i] || other.Max[i] > b.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (b AABB) Union(other AABB) AABB {
+	for i := 0; i < 3; i++ {
+		if other.Min[i] < b.Min[i] {
+			b.Min[i] = other.Min[i]
+		}
+		if other.Max[i] > b.Max[i] {
+			b.Max[i] = other.Max[i]
+		}
+	}
+	return b
+}
+
+// Expand grows the box by margin on every side
+func (b AABB) Expand(margin float32) AABB {
+	offset := mgl32.Vec3{margin, margin, margin}
+	return AABB{b.Min.Sub(offset), b.Max.Add(offset)}
+}
+
+// Extend stretches the box along a displacement, so it covers the box moved
+// by it too
+func (b AABB) Extend(displacement mgl32.Vec3) AABB {
+	for i := 0; i < 3; i++ {
+		if displacement[i] < 0 {
+			b.Min[i] += displacement[i]
+		} else {
+			b.Max[i] += displacement[i]
+		}
+	}
+	return b
+}
+
+// SurfaceArea is the cost of a box in the tree, the chance a random ray hits
+// it grows with its area
+func (b AABB) SurfaceArea() float32 {
+	size := b.Max.Sub(b.Min)
+	return 2 * (size[0]*size[1] + size[1]*size[2] + size[2]*size[0])
+}
+
+// ClosestPoint clamps a point into the box
+func (b AABB) ClosestPoint(point mgl32.Vec3) mgl32.Vec3 {
+	for i := 0; i < 3; i++ {
+		point[i] = mgl32.Clamp(point[i], b.Min[i], b.Max[i])
+	}
+	return point
+}
+
+// OverlapsSphere tests the box against a sphere
+func (b AABB) OverlapsSphere(center mgl32.Vec3, radius float32) bool {
+	return b.ClosestPoint(center).Sub(center).LenSqr() <= radius*radius
+}
+
+// Frustum holds the planes of a view frustum as (normal, distance) with the
+// normals pointing inwards, a point p is inside a plane when
+// normal.p+distance >= 0
+type Frustum [6]mgl32.Vec4
+
+// NewFrustum takes the planes out of a view projection matrix, like
+// camera.VPMatrix
+func NewFrustum(viewProjection mgl32.Mat4) Frustum {
+	row := func(i int) mgl32.Vec4 {
+		return viewProjection.Row(i)
+	}
+	frustum := Frustum{
+		row(3).Add(row(0)), row(3).Sub(row(0)),
+		row(3).Add(row(1)), row(3).Sub(row(1)),
+		row(3).Add(row(2)), row(3).Sub(row(2)),
+	}
+	for i, plane := range frustum {
+		frustum[i] = plane.Mul(1 / plane.Vec3().Len())
+	}
+	return frustum
+}
+
+// OverlapsAABB tests the corner of the box furthest along each plane's normal,
+// boxes next to the frustum's corners can pass without overlapping it
+func (f *Frustum) OverlapsAABB(b AABB) bool {
+	for _, plane := range f {
+		var corner mgl32.Vec3
+		for i := 0; i < 3; i++ {
+			if plane[i] < 0 {
+				corner[i] = b.Min[i]
+			} else {
+				corner[i] = b.Max[i]
+			}
+		}
+		if plane.Vec3().Dot(corner)+plane[3] < 0 {
+			return false
+		}
+	}
+	return true
+}
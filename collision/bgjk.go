@@ -5,11 +5,11 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-func BGJK(shapeA, shapeB []mgl32.Vec3, stepCount int) ([]mgl32.Vec3, int, bool) {
+func BGJK(shapeA, shapeB Shape, stepCount int) ([]mgl32.Vec3, int, bool) {
 	_ = stepCount
 	var simplexStack [4]mgl32.Vec3
 	simplex := simplexStack[:]
-	simplex[0] = shapeA[0].Sub(shapeB[0])
+	simplex[0] = support(shapeA, shapeB, mgl32.Vec3{1, 0, 0})
 	order := 0
 
 	dir := simplex[0].Mul(-1)
@@ -188,20 +188,6 @@ func doSimplex3(simplex []mgl32.Vec3, dir *mgl32.Vec3, order *int) bool {
 	return true
 }
 
-func support(shapeA, shapeB []mgl32.Vec3, dir mgl32.Vec3) mgl32.Vec3 {
-	maxA, minB := shapeA[0].Dot(dir), shapeB[0].Dot(dir)
-	indA, indB := 0, 0
-	for i := 1; i < len(shapeA); i++ {
-		if temp := shapeA[i].Dot(dir); temp > maxA {
-			maxA = temp
-			indA = i
-		}
-	}
-	for i := 1; i < len(shapeB); i++ {
-		if temp := shapeB[i].Dot(dir); temp < minB {
-			minB = temp
-			indB = i
-		}
-	}
-	return shapeA[indA].Sub(shapeB[indB])
+func support(shapeA, shapeB Shape, dir mgl32.Vec3) mgl32.Vec3 {
+	return supportPair(shapeA, shapeB, dir).point
 }
@@ -0,0 +1,106 @@
+package collision
+
+import (
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Pair is two proxies whose fattened boxes overlap, A is the lower one
+type Pair struct {
+	A, B int
+}
+
+func newPair(a, b int) Pair {
+	if a > b {
+		a, b = b, a
+	}
+	return Pair{a, b}
+}
+
+// Broadphase finds the proxies that may touch with a DynamicTree. It keeps
+// the overlapping pairs between updates, so UpdatePairs only queries the tree
+// for the proxies that moved and reports the pairs that came and went
+type Broadphase struct {
+	Tree    *DynamicTree
+	moved   map[int]bool
+	pairs   map[Pair]bool
+	removed []Pair
+}
+
+func NewBroadphase() *Broadphase {
+	return &Broadphase{Tree: NewDynamicTree(), moved: make(map[int]bool), pairs: make(map[Pair]bool)}
+}
+
+// CreateProxy adds a box, its pairs are found on the next update
+func (b *Broadphase) CreateProxy(bounds AABB) int {
+	proxy := b.Tree.Insert(bounds)
+	b.moved[proxy] = true
+	return proxy
+}
+
+// DestroyProxy removes a box, its pairs are reported as removed on the next
+// update
+func (b *Broadphase) DestroyProxy(proxy int) {
+	for pair := range b.pairs {
+		if pair.A == proxy || pair.B == proxy {
+			delete(b.pairs, pair)
+			b.removed = append(b.removed, pair)
+		}
+	}
+	delete(b.moved, proxy)
+	b.Tree.Remove(proxy)
+}
+
+// MoveProxy updates the box of a proxy, see DynamicTree.Move
+func (b *Broadphase) MoveProxy(proxy int, bounds AABB, displacement mgl32.Vec3) {
+	if b.Tree.Move(proxy, bounds, displacement) {
+		b.moved[proxy] = true
+	}
+}
+
+// UpdatePairs finds the pairs of the proxies that moved out of their fattened
+// boxes since the last update and drops the pairs they left. Both lists are
+// sorted
+func (b *Broadphase) UpdatePairs() (added, removed []Pair) {
+	removed = b.removed
+	b.removed = nil
+	for pair := range b.pairs {
+		if (b.moved[pair.A] || b.moved[pair.B]) && !b.Tree.FatBounds(pair.A).Overlaps(b.Tree.FatBounds(pair.B)) {
+			delete(b.pairs, pair)
+			removed = append(removed, pair)
+		}
+	}
+	for proxy := range b.moved {
+		b.Tree.QueryAABB(b.Tree.FatBounds(proxy), func(other int) bool {
+			if pair := newPair(proxy, other); other != proxy && !b.pairs[pair] {
+				b.pairs[pair] = true
+				added = append(added, pair)
+			}
+			return true
+		})
+		delete(b.moved, proxy)
+	}
+	sortPairs(added)
+	sortPairs(removed)
+	return added, removed
+}
+
+// Pairs lists the overlapping pairs as of the last update, sorted
+func (b *Broadphase) Pairs() []Pair {
+	pairs := make([]Pair, 0, len(b.pairs))
+	for pair := range b.pairs {
+		pairs = append(pairs, pair)
+	}
+	sortPairs(pairs)
+	return pairs
+}
+
+func sortPairs(pairs []Pair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+}
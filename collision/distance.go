@@ -0,0 +1,193 @@
+package collision
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Proximity is how far apart two separated shapes are. PointA and PointB are
+// the closest points of the shapes, Simplex holds the up to four points of
+// the Minkowski difference A-B whose hull holds the closest one to the origin
+type Proximity struct {
+	Distance float32
+	PointA   mgl32.Vec3
+	PointB   mgl32.Vec3
+	Simplex  []mgl32.Vec3
+}
+
+const gjkMaxIterations = 64
+
+// Distance measures the gap between two shapes with GJK, to within tolerance.
+// It returns false with a zero distance when the shapes overlap
+func Distance(shapeA, shapeB Shape, tolerance float32) (Proximity, bool) {
+	var simplexStack [4]supportPoint
+	var weights [4]float32
+	simplex, closest, overlapping := gjk(shapeA, shapeB, tolerance, simplexStack[:0], &weights)
+	if overlapping {
+		return Proximity{}, false
+	}
+	result := Proximity{Distance: closest.Len()}
+	for i := range simplex {
+		result.PointA = result.PointA.Add(simplex[i].a.Mul(weights[i]))
+		result.PointB = result.PointB.Add(simplex[i].b.Mul(weights[i]))
+		result.Simplex = append(result.Simplex, simplex[i].point)
+	}
+	return result, true
+}
+
+// gjk walks the simplex towards the origin until it holds it, reporting an
+// overlap, or until it gets no closer than the tolerance. The simplex is built
+// in the given slice and weights holds the barycentric weights of the closest
+// point
+func gjk(shapeA, shapeB Shape, tolerance float32, simplex []supportPoint, weights *[4]float32) ([]supportPoint, mgl32.Vec3, bool) {
+	simplex = append(simplex, supportPair(shapeA, shapeB, mgl32.Vec3{1, 0, 0}))
+	*weights = [4]float32{1}
+	closest := simplex[0].point
+	for i := 0; i < gjkMaxIterations; i++ {
+		if closest.Len() <= epaEpsilon {
+			return simplex, closest, true
+		}
+		next := supportPair(shapeA, shapeB, closest.Mul(-1))
+		//The new point does not get closer to the origin than the tolerance
+		if closest.Dot(closest)-closest.Dot(next.point) <= tolerance*closest.Len() || containsPoint(simplex, next.point) {
+			break
+		}
+		simplex = append(simplex, next)
+		var inside bool
+		closest, simplex, inside = closestOnSimplex(simplex, weights)
+		if inside {
+			return simplex, mgl32.Vec3{}, true
+		}
+	}
+	return simplex, closest, false
+}
+
+func containsPoint(simplex []supportPoint, point mgl32.Vec3) bool {
+	for i := range simplex {
+		if simplex[i].point == point {
+			return true
+		}
+	}
+	return false
+}
+
+// closestOnSimplex finds the point of the simplex closest to the origin and
+// shrinks the simplex to the vertices it is made of, with their barycentric
+// weights. It reports whether a tetrahedron holds the origin
+func closestOnSimplex(simplex []supportPoint, weights *[4]float32) (mgl32.Vec3, []supportPoint, bool) {
+	switch len(simplex) {
+	case 2:
+		return closestOnSegment(simplex, weights), reduceSimplex(simplex, weights), false
+	case 3:
+		return closestOnTriangle(simplex, weights), reduceSimplex(simplex, weights), false
+	}
+	//Test the faces the origin is in front of, seen from the opposite vertex
+	a, b, c, d := simplex[0], simplex[1], simplex[2], simplex[3]
+	faces := [4][4]supportPoint{{a, b, c, d}, {a, c, d, b}, {a, d, b, c}, {b, d, c, a}}
+	best, bestDistance := mgl32.Vec3{}, float32(-1)
+	var bestFace [3]supportPoint
+	var bestWeights [4]float32
+	for _, face := range faces {
+		normal := face[1].point.Sub(face[0].point).Cross(face[2].point.Sub(face[0].point))
+		side := normal.Dot(face[0].point.Mul(-1))
+		opposite := normal.Dot(face[3].point.Sub(face[0].point))
+		//Every face of a flat tetrahedron is a candidate
+		if opposite != 0 && side*opposite >= 0 {
+			continue
+		}
+		triangle := [3]supportPoint{face[0], face[1], face[2]}
+		var faceWeights [4]float32
+		point := closestOnTriangle(triangle[:], &faceWeights)
+		if distance := point.Len(); bestDistance < 0 || distance < bestDistance {
+			best, bestDistance, bestFace, bestWeights = point, distance, triangle, faceWeights
+		}
+	}
+	if bestDistance < 0 {
+		return mgl32.Vec3{}, simplex, true
+	}
+	copy(simplex, bestFace[:])
+	*weights = bestWeights
+	return best, reduceSimplex(simplex[:3], weights), false
+}
+
+// closestOnSegment projects the origin onto the segment
+func closestOnSegment(simplex []supportPoint, weights *[4]float32) mgl32.Vec3 {
+	a, b := simplex[0].point, simplex[1].point
+	ab := b.Sub(a)
+	t := float32(0)
+	if length := ab.Dot(ab); length > 0 {
+		t = mgl32.Clamp(a.Mul(-1).Dot(ab)/length, 0, 1)
+	}
+	weights[0], weights[1] = 1-t, t
+	return a.Add(ab.Mul(t))
+}
+
+// closestOnTriangle finds the closest point to the origin by the Voronoi
+// region of the triangle it falls in
+func closestOnTriangle(simplex []supportPoint, weights *[4]float32) mgl32.Vec3 {
+	a, b, c := simplex[0].point, simplex[1].point, simplex[2].point
+	ab, ac, ap := b.Sub(a), c.Sub(a), a.Mul(-1)
+	*weights = [4]float32{}
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		weights[0] = 1
+		return a
+	}
+	bp := b.Mul(-1)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		weights[1] = 1
+		return b
+	}
+	if vc := d1*d4 - d3*d2; vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		weights[0], weights[1] = 1-v, v
+		return a.Add(ab.Mul(v))
+	}
+	cp := c.Mul(-1)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		weights[2] = 1
+		return c
+	}
+	if vb := d5*d2 - d1*d6; vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		weights[0], weights[2] = 1-w, w
+		return a.Add(ac.Mul(w))
+	}
+	if va := d3*d6 - d5*d4; va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		weights[1], weights[2] = 1-w, w
+		return b.Add(c.Sub(b).Mul(w))
+	}
+	va, vb, vc := d3*d6-d5*d4, d5*d2-d1*d6, d1*d4-d3*d2
+	if va+vb+vc == 0 {
+		//A triangle without area is as close as its closest edge
+		best, bestWeights := mgl32.Vec3{}, [4]float32{}
+		for i, edge := range [3][2]int{{0, 1}, {1, 2}, {0, 2}} {
+			var edgeWeights [4]float32
+			point := closestOnSegment([]supportPoint{simplex[edge[0]], simplex[edge[1]]}, &edgeWeights)
+			if i == 0 || point.Len() < best.Len() {
+				best, bestWeights = point, [4]float32{}
+				bestWeights[edge[0]], bestWeights[edge[1]] = edgeWeights[0], edgeWeights[1]
+			}
+		}
+		*weights = bestWeights
+		return best
+	}
+	denominator := 1 / (va + vb + vc)
+	v, w := vb*denominator, vc*denominator
+	weights[0], weights[1], weights[2] = 1-v-w, v, w
+	return a.Add(ab.Mul(v)).Add(ac.Mul(w))
+}
+
+// reduceSimplex drops the vertices without weight
+func reduceSimplex(simplex []supportPoint, weights *[4]float32) []supportPoint {
+	reduced := simplex[:0]
+	var reducedWeights [4]float32
+	for i := range simplex {
+		if weights[i] > 0 {
+			reducedWeights[len(reduced)] = weights[i]
+			reduced = append(reduced, simplex[i])
+		}
+	}
+	*weights = reducedWeights
+	return reduced
+}
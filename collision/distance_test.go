@@ -0,0 +1,128 @@
+package collision
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// minkowskiCloud is every difference of a point of A and a point of B, like
+// calcMinkowskiDiff
+func minkowskiCloud(shapeA, shapeB []mgl32.Vec3) []mgl32.Vec3 {
+	var cloud []mgl32.Vec3
+	for _, a := range shapeA {
+		for _, b := range shapeB {
+			cloud = append(cloud, a.Sub(b))
+		}
+	}
+	return cloud
+}
+
+func segmentDistance(a, b mgl32.Vec3) float32 {
+	ab := b.Sub(a)
+	if ab.LenSqr() == 0 {
+		return a.Len()
+	}
+	t := mgl32.Clamp(a.Mul(-1).Dot(ab)/ab.LenSqr(), 0, 1)
+	return a.Add(ab.Mul(t)).Len()
+}
+
+// triangleDistance is the distance of the origin from a triangle, by its
+// projection on the plane when it falls inside and by the edges otherwise
+func triangleDistance(a, b, c mgl32.Vec3) float32 {
+	normal := b.Sub(a).Cross(c.Sub(a))
+	if normal.Len() > 1e-6 {
+		normal = normal.Normalize()
+		projection := normal.Mul(normal.Dot(a))
+		inside := true
+		for _, edge := range [3][2]mgl32.Vec3{{a, b}, {b, c}, {c, a}} {
+			if edge[1].Sub(edge[0]).Cross(projection.Sub(edge[0])).Dot(normal) < 0 {
+				inside = false
+			}
+		}
+		if inside {
+			return projection.Len()
+		}
+	}
+	return float32(math.Min(float64(segmentDistance(a, b)), math.Min(float64(segmentDistance(b, c)), float64(segmentDistance(c, a)))))
+}
+
+// bruteForceDistance is the distance of the origin from the hull of the
+// cloud, the closest point of which lies on one of its triangles
+func bruteForceDistance(cloud []mgl32.Vec3) float32 {
+	best := float32(math.MaxFloat32)
+	for i := range cloud {
+		for j := i + 1; j < len(cloud); j++ {
+			for k := j + 1; k < len(cloud); k++ {
+				if distance := triangleDistance(cloud[i], cloud[j], cloud[k]); distance < best {
+					best = distance
+				}
+			}
+		}
+	}
+	return best
+}
+
+func TestDistanceBoxes(t *testing.T) {
+	a := box(mgl32.Vec3{}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())
+	b := box(mgl32.Vec3{3, 0.5, 0}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())
+	proximity, ok := Distance(a, b, 1e-5)
+	if !ok || mgl32.Abs(proximity.Distance-1) > 1e-4 {
+		t.Fatalf("expected the boxes 1 apart, got %+v, %v", proximity, ok)
+	}
+	if mgl32.Abs(proximity.PointA[0]-1) > 1e-4 || mgl32.Abs(proximity.PointB[0]-2) > 1e-4 {
+		t.Errorf("expected the closest points on the facing sides, got %v and %v", proximity.PointA, proximity.PointB)
+	}
+	if len(proximity.Simplex) == 0 || len(proximity.Simplex) > 3 {
+		t.Errorf("expected a witness simplex of one to three points, got %v", proximity.Simplex)
+	}
+
+	if _, ok := Distance(a, box(mgl32.Vec3{1.5, 0, 0}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent()), 1e-5); ok {
+		t.Errorf("expected no distance for overlapping boxes")
+	}
+	//Corner to corner
+	c := box(mgl32.Vec3{3, 3, 3}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())
+	if proximity, ok := Distance(a, c, 1e-5); !ok || mgl32.Abs(proximity.Distance-float32(math.Sqrt(3))) > 1e-4 || len(proximity.Simplex) != 1 {
+		t.Errorf("expected the corners sqrt(3) apart with a single point simplex, got %+v, %v", proximity, ok)
+	}
+}
+
+func TestDistanceMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	randomVec := func() mgl32.Vec3 {
+		return mgl32.Vec3{random.Float32() - 0.5, random.Float32() - 0.5, random.Float32() - 0.5}
+	}
+	for i := 0; i < 30; i++ {
+		rotationA := mgl32.QuatRotate(random.Float32()*2*math.Pi, randomVec().Normalize())
+		rotationB := mgl32.QuatRotate(random.Float32()*2*math.Pi, randomVec().Normalize())
+		a := box(randomVec(), mgl32.Vec3{1, 0.5, 0.8}, rotationA)
+		b := box(randomVec().Mul(8), mgl32.Vec3{0.6, 1, 0.4}, rotationB)
+		bruteForce := bruteForceDistance(minkowskiCloud(a, b))
+		proximity, ok := Distance(a, b, 1e-6)
+		if _, overlapping := Penetration(a, b); overlapping {
+			if ok {
+				t.Errorf("case %v: expected no distance for overlapping shapes, got %+v", i, proximity)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("case %v: expected a distance of %v", i, bruteForce)
+			continue
+		}
+		if mgl32.Abs(proximity.Distance-bruteForce) > 1e-3 {
+			t.Errorf("case %v: expected a distance close to %v, got %v", i, bruteForce, proximity.Distance)
+		}
+		if mgl32.Abs(proximity.PointB.Sub(proximity.PointA).Len()-proximity.Distance) > 1e-3 {
+			t.Errorf("case %v: expected the closest points %v apart, got %v and %v", i, proximity.Distance, proximity.PointA, proximity.PointB)
+		}
+		//The closest points face each other across the gap
+		if proximity.Distance > 1e-3 {
+			direction := proximity.PointB.Sub(proximity.PointA).Normalize()
+			if gap := -overlap(a, b, direction); mgl32.Abs(gap-proximity.Distance) > 1e-3 {
+				t.Errorf("case %v: expected a gap of %v along %v, got %v", i, proximity.Distance, direction, gap)
+			}
+		}
+	}
+}
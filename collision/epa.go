@@ -0,0 +1,285 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Contact describes how far two shapes overlap. Moving shape A by
+// Normal*Depth separates them, PointA and PointB are the points of each shape
+// deepest inside the other, so PointA+Normal*Depth is PointB
+type Contact struct {
+	Depth  float32
+	Normal mgl32.Vec3
+	PointA mgl32.Vec3
+	PointB mgl32.Vec3
+}
+
+const (
+	epaMaxIterations = 64
+	//Distance the polytope has to grow by to keep expanding
+	epaTolerance = 1e-4
+	//Smaller lengths and distances count as zero
+	epaEpsilon = 1e-6
+)
+
+// supportPoint is a point of the Minkowski difference A-B, along with the
+// points of both shapes it is made of
+type supportPoint struct {
+	point, a, b mgl32.Vec3
+}
+
+// epaFace is a triangle of the polytope with its outward normal and distance
+// from the origin
+type epaFace struct {
+	vertices [3]int
+	normal   mgl32.Vec3
+	distance float32
+}
+
+type epaEdge struct {
+	from, to int
+}
+
+// Penetration tests two shapes with GJK and measures their overlap with EPA
+func Penetration(shapeA, shapeB Shape) (Contact, bool) {
+	var simplexStack [4]supportPoint
+	var weights [4]float32
+	simplex, _, overlapping := gjk(shapeA, shapeB, epaEpsilon, simplexStack[:0], &weights)
+	if !overlapping {
+		return Contact{}, false
+	}
+	//Shapes that only touch stop GJK before it has a tetrahedron, EPA
+	//completes it and checks it holds the origin
+	return expandSimplex(shapeA, shapeB, simplex)
+}
+
+// EPA expands the simplex returned by BGJK into the polytope of the Minkowski
+// difference until it finds the face closest to the origin, which gives the
+// depth and direction of the overlap. Simplices with fewer than four points,
+// or with points that do not span a volume, are completed first. It returns
+// false when the simplex does not hold the origin, so the shapes do not
+// overlap, or when the Minkowski difference is flat. BGJK only keeps the
+// points of the difference, so EPA takes hulls to find the shape points they
+// are made of in, other shapes go through Penetration
+func EPA(shapeA, shapeB ConvexHull, simplex []mgl32.Vec3, order int) (Contact, bool) {
+	vertices := make([]supportPoint, 0, 4)
+	for _, point := range simplex[:order+1] {
+		vertices = append(vertices, findSupportPoint(shapeA, shapeB, point))
+	}
+	return expandSimplex(shapeA, shapeB, vertices)
+}
+
+func expandSimplex(shapeA, shapeB Shape, simplex []supportPoint) (Contact, bool) {
+	vertices := append(make([]supportPoint, 0, 4+epaMaxIterations), simplex...)
+	vertices, ok := completeSimplex(shapeA, shapeB, vertices)
+	if !ok {
+		return Contact{}, false
+	}
+	//The origin can lie on the surface, so faces are turned away from the
+	//centre of the tetrahedron, which stays inside as the polytope grows
+	interior := vertices[0].point.Add(vertices[1].point).Add(vertices[2].point).Add(vertices[3].point).Mul(0.25)
+	faces := make([]epaFace, 0, 4+2*epaMaxIterations)
+	for _, triangle := range [4][3]int{{0, 1, 2}, {0, 3, 1}, {0, 2, 3}, {1, 3, 2}} {
+		face := newFace(vertices, triangle, interior)
+		if face.distance < -epaTolerance {
+			return Contact{}, false
+		}
+		faces = append(faces, face)
+	}
+
+	closest := closestFace(faces)
+	for i := 0; i < epaMaxIterations; i++ {
+		face := &faces[closest]
+		next := supportPair(shapeA, shapeB, face.normal)
+		if next.point.Dot(face.normal)-face.distance < epaTolerance {
+			break
+		}
+		vertices = append(vertices, next)
+		expanded, grew := expandPolytope(vertices, faces, len(vertices)-1, interior)
+		if !grew {
+			break
+		}
+		faces = expanded
+		closest = closestFace(faces)
+	}
+	return faceContact(vertices, &faces[closest]), true
+}
+
+// findSupportPoint recovers the shape points a point of the Minkowski
+// difference was made of, BGJK only keeps their difference
+func findSupportPoint(shapeA, shapeB ConvexHull, point mgl32.Vec3) supportPoint {
+	best, bestDistance := supportPoint{}, float32(math.MaxFloat32)
+	for _, a := range shapeA {
+		for _, b := range shapeB {
+			if distance := a.Sub(b).Sub(point).LenSqr(); distance < bestDistance {
+				best, bestDistance = supportPoint{a.Sub(b), a, b}, distance
+				if distance == 0 {
+					return best
+				}
+			}
+		}
+	}
+	return best
+}
+
+// completeSimplex keeps the points that span a new dimension and searches the
+// Minkowski difference for the missing ones, until they form a tetrahedron
+func completeSimplex(shapeA, shapeB Shape, vertices []supportPoint) ([]supportPoint, bool) {
+	result := vertices[:0]
+	for _, vertex := range vertices {
+		if len(result) < 4 && spanDistance(result, vertex.point) > epaEpsilon {
+			result = append(result, vertex)
+		}
+	}
+	axes := [6]mgl32.Vec3{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}}
+	for len(result) < 4 {
+		var directions []mgl32.Vec3
+		switch len(result) {
+		case 0, 1:
+			directions = axes[:]
+		case 2:
+			//Perpendiculars of the segment
+			line := result[1].point.Sub(result[0].point)
+			axis := axes[0]
+			if mgl32.Abs(line[0]) > mgl32.Abs(line[1]) {
+				axis = axes[2]
+			}
+			u := line.Cross(axis).Normalize()
+			v := line.Cross(u).Normalize()
+			directions = []mgl32.Vec3{u, u.Mul(-1), v, v.Mul(-1)}
+		case 3:
+			normal := result[1].point.Sub(result[0].point).Cross(result[2].point.Sub(result[0].point))
+			directions = []mgl32.Vec3{normal, normal.Mul(-1)}
+		}
+		best, bestDistance := supportPoint{}, float32(0)
+		for _, direction := range directions {
+			candidate := supportPair(shapeA, shapeB, direction)
+			if distance := spanDistance(result, candidate.point); distance > bestDistance {
+				best, bestDistance = candidate, distance
+			}
+		}
+		if bestDistance <= epaEpsilon {
+			return nil, false
+		}
+		result = append(result, best)
+	}
+	return result, true
+}
+
+// spanDistance is the distance of a point from the point, line or plane
+// spanned by the vertices
+func spanDistance(vertices []supportPoint, point mgl32.Vec3) float32 {
+	switch len(vertices) {
+	case 0:
+		return float32(math.MaxFloat32)
+	case 1:
+		return point.Sub(vertices[0].point).Len()
+	case 2:
+		line := vertices[1].point.Sub(vertices[0].point)
+		return point.Sub(vertices[0].point).Cross(line).Len() / line.Len()
+	}
+	normal := vertices[1].point.Sub(vertices[0].point).Cross(vertices[2].point.Sub(vertices[0].point))
+	return mgl32.Abs(point.Sub(vertices[0].point).Dot(normal)) / normal.Len()
+}
+
+// newFace builds a face with its normal pointing away from the interior point.
+// Faces too thin to have a normal are never the closest one
+func newFace(vertices []supportPoint, triangle [3]int, interior mgl32.Vec3) epaFace {
+	a, b, c := vertices[triangle[0]].point, vertices[triangle[1]].point, vertices[triangle[2]].point
+	normal := b.Sub(a).Cross(c.Sub(a))
+	if normal.Len() < epaEpsilon {
+		return epaFace{vertices: triangle, distance: float32(math.MaxFloat32)}
+	}
+	normal = normal.Normalize()
+	if normal.Dot(a.Sub(interior)) < 0 {
+		normal = normal.Mul(-1)
+		triangle[1], triangle[2] = triangle[2], triangle[1]
+	}
+	return epaFace{vertices: triangle, normal: normal, distance: normal.Dot(a)}
+}
+
+func closestFace(faces []epaFace) int {
+	closest := 0
+	for i := range faces {
+		if faces[i].distance < faces[closest].distance {
+			closest = i
+		}
+	}
+	return closest
+}
+
+// expandPolytope replaces the faces the new vertex sees with faces joining it
+// to the edges around the hole they leave. It reports false when the vertex
+// sees no face, which only happens through rounding
+func expandPolytope(vertices []supportPoint, faces []epaFace, vertex int, interior mgl32.Vec3) ([]epaFace, bool) {
+	point := vertices[vertex].point
+	var horizon []epaEdge
+	kept := faces[:0]
+	for _, face := range faces {
+		if face.distance == float32(math.MaxFloat32) || face.normal.Dot(point.Sub(vertices[face.vertices[0]].point)) <= epaEpsilon {
+			kept = append(kept, face)
+			continue
+		}
+		//Edges shared by two removed faces are inside the hole
+		for i := 0; i < 3; i++ {
+			edge := epaEdge{face.vertices[i], face.vertices[(i+1)%3]}
+			shared := false
+			for j := range horizon {
+				if horizon[j].from == edge.to && horizon[j].to == edge.from {
+					horizon = append(horizon[:j], horizon[j+1:]...)
+					shared = true
+					break
+				}
+			}
+			if !shared {
+				horizon = append(horizon, edge)
+			}
+		}
+	}
+	if len(horizon) == 0 {
+		return faces, false
+	}
+	for _, edge := range horizon {
+		kept = append(kept, newFace(vertices, [3]int{edge.from, edge.to, vertex}, interior))
+	}
+	return kept, true
+}
+
+// faceContact projects the origin onto the face and finds the matching points
+// of both shapes by its barycentric coordinates
+func faceContact(vertices []supportPoint, face *epaFace) Contact {
+	a, b, c := &vertices[face.vertices[0]], &vertices[face.vertices[1]], &vertices[face.vertices[2]]
+	depth := face.distance
+	if depth < 0 {
+		depth = 0
+	}
+	projection := face.normal.Mul(face.distance)
+	u, v, w := barycentric(projection, a.point, b.point, c.point)
+	return Contact{
+		Depth:  depth,
+		Normal: face.normal.Mul(-1),
+		PointA: a.a.Mul(u).Add(b.a.Mul(v)).Add(c.a.Mul(w)),
+		PointB: a.b.Mul(u).Add(b.b.Mul(v)).Add(c.b.Mul(w)),
+	}
+}
+
+func barycentric(p, a, b, c mgl32.Vec3) (float32, float32, float32) {
+	ab, ac, ap := b.Sub(a), c.Sub(a), p.Sub(a)
+	d00, d01, d11 := ab.Dot(ab), ab.Dot(ac), ac.Dot(ac)
+	d20, d21 := ap.Dot(ab), ap.Dot(ac)
+	denominator := d00*d11 - d01*d01
+	if mgl32.Abs(denominator) < epaEpsilon*epaEpsilon {
+		return 1.0 / 3, 1.0 / 3, 1.0 / 3
+	}
+	v := (d11*d20 - d01*d21) / denominator
+	w := (d00*d21 - d01*d20) / denominator
+	return 1 - v - w, v, w
+}
+
+// supportPair is support keeping the shape points the result is made of
+func supportPair(shapeA, shapeB Shape, dir mgl32.Vec3) supportPoint {
+	a, b := shapeA.Support(dir), shapeB.Support(dir.Mul(-1))
+	return supportPoint{a.Sub(b), a, b}
+}
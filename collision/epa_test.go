@@ -0,0 +1,127 @@
+package collision
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// box returns the corners of a box with the given half extents, rotated and
+// moved to center
+func box(center, halfExtents mgl32.Vec3, rotation mgl32.Quat) ConvexHull {
+	var corners ConvexHull
+	for i := 0; i < 8; i++ {
+		corner := mgl32.Vec3{halfExtents[0], halfExtents[1], halfExtents[2]}
+		for axis := 0; axis < 3; axis++ {
+			if i&(1<<uint(axis)) != 0 {
+				corner[axis] = -corner[axis]
+			}
+		}
+		corners = append(corners, rotation.Rotate(corner).Add(center))
+	}
+	return corners
+}
+
+// overlap is how far A has to move along -direction to stop overlapping B
+func overlap(shapeA, shapeB Shape, direction mgl32.Vec3) float32 {
+	return supportPair(shapeA, shapeB, direction).point.Dot(direction)
+}
+
+func TestEPABoxes(t *testing.T) {
+	a := box(mgl32.Vec3{}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())
+	b := box(mgl32.Vec3{1.5, 0.2, 0.1}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())
+	contact, ok := Penetration(a, b)
+	if !ok {
+		t.Fatal("expected the boxes to overlap")
+	}
+	if mgl32.Abs(contact.Depth-0.5) > 1e-4 || contact.Normal.Sub(mgl32.Vec3{-1, 0, 0}).Len() > 1e-4 {
+		t.Errorf("expected a depth of 0.5 along -x, got %+v", contact)
+	}
+	if mgl32.Abs(contact.PointA[0]-1) > 1e-4 || mgl32.Abs(contact.PointB[0]-0.5) > 1e-4 {
+		t.Errorf("expected the contact points on the facing sides, got %v and %v", contact.PointA, contact.PointB)
+	}
+	if contact.PointA.Add(contact.Normal.Mul(contact.Depth)).Sub(contact.PointB).Len() > 1e-4 {
+		t.Errorf("expected the push out to join the contact points, got %+v", contact)
+	}
+
+	if _, ok := Penetration(a, box(mgl32.Vec3{2.5, 0, 0}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())); ok {
+		t.Errorf("expected no contact for separate boxes")
+	}
+	//Boxes that touch overlap by nothing
+	if contact, ok := Penetration(a, box(mgl32.Vec3{2, 0.5, 0}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())); ok && contact.Depth > 1e-4 {
+		t.Errorf("expected no depth for touching boxes, got %+v", contact)
+	}
+}
+
+func TestEPADegenerateSimplex(t *testing.T) {
+	a := box(mgl32.Vec3{}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())
+	b := box(mgl32.Vec3{0, 1.8, 0}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())
+	//BGJK stops early when the origin lies on a segment or a triangle of the
+	//Minkowski difference, these are completed into a tetrahedron
+	simplices := [][]mgl32.Vec3{
+		{a[0].Sub(b[2]), a[2].Sub(b[2])},
+		{a[3].Sub(b[2]), a[2].Sub(b[3]), a[0].Sub(b[2])},
+	}
+	for _, simplex := range simplices {
+		contact, ok := EPA(a, b, simplex, len(simplex)-1)
+		if !ok || mgl32.Abs(contact.Depth-0.2) > 1e-4 || contact.Normal.Sub(mgl32.Vec3{0, -1, 0}).Len() > 1e-4 {
+			t.Errorf("simplex %v: expected a depth of 0.2 along -y, got %+v, %v", simplex, contact, ok)
+		}
+	}
+	//A flat Minkowski difference has no volume to expand
+	flat := ConvexHull{{0, 0, 0}, {1, 0, 0}, {0, 0, 1}}
+	if _, ok := EPA(flat, flat, []mgl32.Vec3{{}}, 0); ok {
+		t.Errorf("expected no contact for flat shapes")
+	}
+}
+
+func TestEPAMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomRotation := func() mgl32.Quat {
+		return mgl32.QuatRotate(random.Float32()*2*math.Pi, mgl32.Vec3{random.Float32() - 0.5, random.Float32() - 0.5, random.Float32() - 0.5}.Normalize())
+	}
+	for i := 0; i < 20; i++ {
+		rotationA, rotationB := randomRotation(), randomRotation()
+		a := box(mgl32.Vec3{}, mgl32.Vec3{1, 0.5, 0.8}, rotationA)
+		center := mgl32.Vec3{random.Float32() - 0.5, random.Float32() - 0.5, random.Float32() - 0.5}.Mul(2)
+		b := box(center, mgl32.Vec3{0.6, 1, 0.4}, rotationB)
+		//The shallowest overlap of two boxes is along one of their face
+		//normals or the cross product of two of their edges
+		var axes []mgl32.Vec3
+		for _, x := range []mgl32.Vec3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+			axes = append(axes, rotationA.Rotate(x), rotationB.Rotate(x))
+			for _, y := range []mgl32.Vec3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+				if axis := rotationA.Rotate(x).Cross(rotationB.Rotate(y)); axis.Len() > 1e-3 {
+					axes = append(axes, axis.Normalize())
+				}
+			}
+		}
+		bruteForce := float32(math.MaxFloat32)
+		for _, axis := range axes {
+			for _, direction := range []mgl32.Vec3{axis, axis.Mul(-1)} {
+				if depth := overlap(a, b, direction); depth < bruteForce {
+					bruteForce = depth
+				}
+			}
+		}
+		contact, ok := Penetration(a, b)
+		if bruteForce <= 0 {
+			if ok && contact.Depth > 1e-4 {
+				t.Errorf("case %v: expected no contact, got %+v", i, contact)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("case %v: expected an overlap of %v", i, bruteForce)
+			continue
+		}
+		if mgl32.Abs(contact.Depth-bruteForce) > 1e-3 {
+			t.Errorf("case %v: expected a depth close to %v, got %v", i, bruteForce, contact.Depth)
+		}
+		if depth := overlap(a, b, contact.Normal.Mul(-1)); mgl32.Abs(depth-contact.Depth) > 1e-3 {
+			t.Errorf("case %v: expected the normal to separate the shapes by %v, got %v", i, contact.Depth, depth)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package collision
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Shape is a convex shape described by its support function, the point of
+// the shape furthest along a direction. The direction does not have to be
+// normalized and can be zero, in which case any point of the shape will do
+type Shape interface {
+	Support(dir mgl32.Vec3) mgl32.Vec3
+}
+
+// Sphere is a ball around Center
+type Sphere struct {
+	Center mgl32.Vec3
+	Radius float32
+}
+
+// Capsule is a segment from A to B grown by Radius
+type Capsule struct {
+	A, B   mgl32.Vec3
+	Radius float32
+}
+
+// Box is an axis aligned box, wrap it in Transformed to rotate it
+type Box struct {
+	Center      mgl32.Vec3
+	HalfExtents mgl32.Vec3
+}
+
+// Cylinder stands along the y axis, wrap it in Transformed to tilt it
+type Cylinder struct {
+	Center     mgl32.Vec3
+	HalfHeight float32
+	Radius     float32
+}
+
+// ConvexHull is the hull of a point cloud, like the points returned by
+// GenerateCollisionPointsFromConvexMesh
+type ConvexHull []mgl32.Vec3
+
+// Transformed rotates and then moves a shape without touching its points, the
+// direction is brought into the shape's space on every support query instead
+type Transformed struct {
+	Shape    Shape
+	Position mgl32.Vec3
+	Rotation mgl32.Quat
+}
+
+// NewTransformed wraps a shape with an identity rotation
+func NewTransformed(shape Shape, position mgl32.Vec3) *Transformed {
+	return &Transformed{Shape: shape, Position: position, Rotation: mgl32.QuatIdent()}
+}
+
+func (s Sphere) Support(dir mgl32.Vec3) mgl32.Vec3 {
+	return s.Center.Add(unit(dir).Mul(s.Radius))
+}
+
+func (c Capsule) Support(dir mgl32.Vec3) mgl32.Vec3 {
+	end := c.A
+	if c.B.Dot(dir) > c.A.Dot(dir) {
+		end = c.B
+	}
+	return end.Add(unit(dir).Mul(c.Radius))
+}
+
+func (b Box) Support(dir mgl32.Vec3) mgl32.Vec3 {
+	result := b.Center
+	for i := 0; i < 3; i++ {
+		if dir[i] < 0 {
+			result[i] -= b.HalfExtents[i]
+		} else {
+			result[i] += b.HalfExtents[i]
+		}
+	}
+	return result
+}
+
+func (c Cylinder) Support(dir mgl32.Vec3) mgl32.Vec3 {
+	result := c.Center.Add(unit(mgl32.Vec3{dir[0], 0, dir[2]}).Mul(c.Radius))
+	if dir[1] < 0 {
+		result[1] -= c.HalfHeight
+	} else {
+		result[1] += c.HalfHeight
+	}
+	return result
+}
+
+func (h ConvexHull) Support(dir mgl32.Vec3) mgl32.Vec3 {
+	best, bestDot := h[0], h[0].Dot(dir)
+	for i := 1; i < len(h); i++ {
+		if temp := h[i].Dot(dir); temp > bestDot {
+			best, bestDot = h[i], temp
+		}
+	}
+	return best
+}
+
+func (t *Transformed) Support(dir mgl32.Vec3) mgl32.Vec3 {
+	local := t.Shape.Support(t.Rotation.Conjugate().Rotate(dir))
+	return t.Rotation.Rotate(local).Add(t.Position)
+}
+
+// unit normalizes a direction, leaving the zero vector as it is
+func unit(dir mgl32.Vec3) mgl32.Vec3 {
+	if length := dir.Len(); length > 0 {
+		return dir.Mul(1 / length)
+	}
+	return dir
+}
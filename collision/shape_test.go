@@ -0,0 +1,72 @@
+package collision
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestShapeSupport(t *testing.T) {
+	rotation := mgl32.QuatRotate(math.Pi/2, mgl32.Vec3{0, 1, 0})
+	tests := []struct {
+		name     string
+		shape    Shape
+		dir      mgl32.Vec3
+		expected mgl32.Vec3
+	}{
+		{"sphere", Sphere{mgl32.Vec3{1, 0, 0}, 2}, mgl32.Vec3{0, 3, 0}, mgl32.Vec3{1, 2, 0}},
+		{"capsule", Capsule{mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 2, 0}, 0.5}, mgl32.Vec3{1, 0, 0}.Add(mgl32.Vec3{0, 1, 0}).Normalize(), mgl32.Vec3{float32(math.Sqrt(0.125)), 2 + float32(math.Sqrt(0.125)), 0}},
+		{"box", Box{mgl32.Vec3{0, 1, 0}, mgl32.Vec3{1, 2, 3}}, mgl32.Vec3{-1, 1, -1}, mgl32.Vec3{-1, 3, -3}},
+		{"cylinder", Cylinder{mgl32.Vec3{}, 1, 2}, mgl32.Vec3{0, -1, 5}, mgl32.Vec3{0, -1, 2}},
+		{"hull", ConvexHull{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, mgl32.Vec3{1, 2, 0}, mgl32.Vec3{0, 1, 0}},
+		{"transformed", &Transformed{Box{mgl32.Vec3{}, mgl32.Vec3{2, 1, 1}}, mgl32.Vec3{0, 5, 0}, rotation}, mgl32.Vec3{0.1, 0.1, -1}, mgl32.Vec3{1, 6, -2}},
+		//A zero direction still lands on the shape
+		{"zero direction", Sphere{mgl32.Vec3{1, 1, 1}, 1}, mgl32.Vec3{}, mgl32.Vec3{1, 1, 1}},
+	}
+	for _, test := range tests {
+		if result := test.shape.Support(test.dir); result.Sub(test.expected).Len() > 1e-5 {
+			t.Errorf("%v: expected %v, got %v", test.name, test.expected, result)
+		}
+	}
+}
+
+func TestAnalyticShapes(t *testing.T) {
+	//Spheres are round, their distance only converges to within the tolerance
+	a, b := Sphere{mgl32.Vec3{}, 1}, Sphere{mgl32.Vec3{3, 1, 0}, 0.5}
+	proximity, ok := Distance(a, b, 1e-6)
+	if expected := float32(math.Sqrt(10)) - 1.5; !ok || mgl32.Abs(proximity.Distance-expected) > 1e-3 {
+		t.Errorf("expected the spheres %v apart, got %+v, %v", expected, proximity, ok)
+	}
+	contact, ok := Penetration(a, Sphere{mgl32.Vec3{0, 1.2, 0}, 0.5})
+	if !ok || mgl32.Abs(contact.Depth-0.3) > 1e-3 || contact.Normal.Sub(mgl32.Vec3{0, -1, 0}).Len() > 1e-2 {
+		t.Errorf("expected a depth of 0.3 along -y, got %+v, %v", contact, ok)
+	}
+
+	//A capsule lying on a box
+	capsule := Capsule{mgl32.Vec3{-1, 1.4, 0}, mgl32.Vec3{1, 1.4, 0}, 0.5}
+	ground := Box{mgl32.Vec3{}, mgl32.Vec3{5, 1, 5}}
+	if contact, ok := Penetration(capsule, ground); !ok || mgl32.Abs(contact.Depth-0.1) > 1e-3 || contact.Normal.Sub(mgl32.Vec3{0, 1, 0}).Len() > 1e-3 {
+		t.Errorf("expected the capsule 0.1 deep in the ground, got %+v, %v", contact, ok)
+	}
+	if proximity, ok := Distance(Cylinder{mgl32.Vec3{0, 2.5, 0}, 1, 1}, ground, 1e-6); !ok || mgl32.Abs(proximity.Distance-0.5) > 1e-4 {
+		t.Errorf("expected the cylinder 0.5 above the ground, got %+v, %v", proximity, ok)
+	}
+
+	//A transformed box matches the hull of its rotated corners
+	rotation := mgl32.QuatRotate(0.7, mgl32.Vec3{1, 1, 0}.Normalize())
+	center, halfExtents := mgl32.Vec3{0.5, 1.2, -0.3}, mgl32.Vec3{1, 0.5, 0.8}
+	transformed := &Transformed{Box{mgl32.Vec3{}, halfExtents}, center, rotation}
+	hull := box(center, halfExtents, rotation)
+	other := box(mgl32.Vec3{}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent())
+	expected, ok := Penetration(hull, other)
+	if !ok {
+		t.Fatal("expected the boxes to overlap")
+	}
+	if result, ok := Penetration(transformed, other); !ok || mgl32.Abs(result.Depth-expected.Depth) > 1e-4 {
+		t.Errorf("expected a depth of %v like the hull, got %+v, %v", expected.Depth, result, ok)
+	}
+	if _, _, collided := BGJK(NewTransformed(other, mgl32.Vec3{3, 0, 0}), other, 128); collided {
+		t.Errorf("expected moved boxes not to collide")
+	}
+}
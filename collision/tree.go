@@ -0,0 +1,297 @@
+package collision
+
+import "github.com/go-gl/mathgl/mgl32"
+
+const (
+	nullNode = -1
+	//Leaves are fattened by the margin, so small moves do not touch the tree
+	treeMargin = 0.1
+	//Moving leaves are stretched this many frames ahead of their displacement
+	treeDisplacementFrames = 2
+)
+
+// treeNode is a leaf holding a proxy or a branch with two children, leaves
+// have no left child. Free nodes are chained through their parents
+type treeNode struct {
+	bounds              AABB
+	parent, left, right int
+	height              int
+}
+
+func (n *treeNode) isLeaf() bool {
+	return n.left == nullNode
+}
+
+// DynamicTree is a bounding volume hierarchy of fattened AABBs that stays
+// balanced as boxes are inserted, moved and removed. Proxies are the indices
+// of the leaves, they stay valid until removed and are reused after
+type DynamicTree struct {
+	nodes  []treeNode
+	root   int
+	free   int
+	Margin float32
+}
+
+func NewDynamicTree() *DynamicTree {
+	return &DynamicTree{root: nullNode, free: nullNode, Margin: treeMargin}
+}
+
+// Insert adds a box to the tree and returns its proxy
+func (t *DynamicTree) Insert(bounds AABB) int {
+	proxy := t.allocate()
+	t.nodes[proxy].bounds = bounds.Expand(t.Margin)
+	t.insertLeaf(proxy)
+	return proxy
+}
+
+// Remove takes a proxy out of the tree
+func (t *DynamicTree) Remove(proxy int) {
+	t.removeLeaf(proxy)
+	t.release(proxy)
+}
+
+// Move updates the box of a proxy that moved by displacement since the last
+// call. The tree only changes when the box leaves the fattened one, Move
+// reports whether it did
+func (t *DynamicTree) Move(proxy int, bounds AABB, displacement mgl32.Vec3) bool {
+	if t.nodes[proxy].bounds.Contains(bounds) {
+		return false
+	}
+	t.removeLeaf(proxy)
+	t.nodes[proxy].bounds = bounds.Expand(t.Margin).Extend(displacement.Mul(treeDisplacementFrames))
+	t.insertLeaf(proxy)
+	return true
+}
+
+// FatBounds is the fattened box the tree keeps for a proxy
+func (t *DynamicTree) FatBounds(proxy int) AABB {
+	return t.nodes[proxy].bounds
+}
+
+// Height is the number of levels below the root, 0 for a single leaf
+func (t *DynamicTree) Height() int {
+	if t.root == nullNode {
+		return 0
+	}
+	return t.nodes[t.root].height
+}
+
+// QueryAABB calls back with every proxy whose fattened box overlaps the box,
+// until the callback returns false
+func (t *DynamicTree) QueryAABB(bounds AABB, callback func(proxy int) bool) {
+	t.query(bounds.Overlaps, callback)
+}
+
+// QuerySphere calls back with every proxy whose fattened box overlaps the
+// sphere, until the callback returns false
+func (t *DynamicTree) QuerySphere(center mgl32.Vec3, radius float32, callback func(proxy int) bool) {
+	t.query(func(bounds AABB) bool {
+		return bounds.OverlapsSphere(center, radius)
+	}, callback)
+}
+
+// QueryFrustum calls back with every proxy whose fattened box is at least
+// partly inside the frustum, until the callback returns false
+func (t *DynamicTree) QueryFrustum(frustum *Frustum, callback func(proxy int) bool) {
+	t.query(frustum.OverlapsAABB, callback)
+}
+
+func (t *DynamicTree) query(overlaps func(AABB) bool, callback func(proxy int) bool) {
+	if t.root == nullNode {
+		return
+	}
+	stack := []int{t.root}
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &t.nodes[index]
+		if !overlaps(node.bounds) {
+			continue
+		}
+		if node.isLeaf() {
+			if !callback(index) {
+				return
+			}
+		} else {
+			stack = append(stack, node.left, node.right)
+		}
+	}
+}
+
+func (t *DynamicTree) allocate() int {
+	if t.free == nullNode {
+		t.nodes = append(t.nodes, treeNode{})
+		t.free = len(t.nodes) - 1
+		t.nodes[t.free].parent = nullNode
+	}
+	index := t.free
+	t.free = t.nodes[index].parent
+	t.nodes[index] = treeNode{parent: nullNode, left: nullNode, right: nullNode}
+	return index
+}
+
+func (t *DynamicTree) release(index int) {
+	t.nodes[index] = treeNode{parent: t.free, left: nullNode, right: nullNode, height: -1}
+	t.free = index
+}
+
+// insertLeaf walks down to the sibling that grows the surface area of the
+// tree the least and pairs the leaf with it under a new branch
+func (t *DynamicTree) insertLeaf(leaf int) {
+	if t.root == nullNode {
+		t.root = leaf
+		t.nodes[leaf].parent = nullNode
+		return
+	}
+	bounds := t.nodes[leaf].bounds
+	index := t.root
+	for !t.nodes[index].isLeaf() {
+		node := &t.nodes[index]
+		area := node.bounds.SurfaceArea()
+		combined := node.bounds.Union(bounds).SurfaceArea()
+		//Cost of pairing the leaf with this node, and the cost every level
+		//below pays for growing it
+		cost := 2 * combined
+		inheritance := 2 * (combined - area)
+		childCost := func(child int) float32 {
+			childBounds := t.nodes[child].bounds
+			cost := childBounds.Union(bounds).SurfaceArea() + inheritance
+			if !t.nodes[child].isLeaf() {
+				cost -= childBounds.SurfaceArea()
+			}
+			return cost
+		}
+		leftCost, rightCost := childCost(node.left), childCost(node.right)
+		if cost < leftCost && cost < rightCost {
+			break
+		}
+		if leftCost < rightCost {
+			index = node.left
+		} else {
+			index = node.right
+		}
+	}
+
+	sibling := index
+	oldParent := t.nodes[sibling].parent
+	newParent := t.allocate()
+	t.nodes[newParent] = treeNode{
+		bounds: t.nodes[sibling].bounds.Union(bounds),
+		parent: oldParent,
+		left:   sibling,
+		right:  leaf,
+		height: t.nodes[sibling].height + 1,
+	}
+	if oldParent == nullNode {
+		t.root = newParent
+	} else if t.nodes[oldParent].left == sibling {
+		t.nodes[oldParent].left = newParent
+	} else {
+		t.nodes[oldParent].right = newParent
+	}
+	t.nodes[sibling].parent = newParent
+	t.nodes[leaf].parent = newParent
+	t.refit(t.nodes[leaf].parent)
+}
+
+// removeLeaf puts the leaf's sibling in place of their parent
+func (t *DynamicTree) removeLeaf(leaf int) {
+	if leaf == t.root {
+		t.root = nullNode
+		return
+	}
+	parent := t.nodes[leaf].parent
+	grandParent := t.nodes[parent].parent
+	sibling := t.nodes[parent].left
+	if sibling == leaf {
+		sibling = t.nodes[parent].right
+	}
+	t.release(parent)
+	t.nodes[sibling].parent = grandParent
+	if grandParent == nullNode {
+		t.root = sibling
+		return
+	}
+	if t.nodes[grandParent].left == parent {
+		t.nodes[grandParent].left = sibling
+	} else {
+		t.nodes[grandParent].right = sibling
+	}
+	t.refit(grandParent)
+}
+
+// refit balances the branches from index up to the root and fixes their boxes
+// and heights
+func (t *DynamicTree) refit(index int) {
+	for index != nullNode {
+		index = t.balance(index)
+		node := &t.nodes[index]
+		left, right := &t.nodes[node.left], &t.nodes[node.right]
+		node.height = 1 + maxInt(left.height, right.height)
+		node.bounds = left.bounds.Union(right.bounds)
+		index = node.parent
+	}
+}
+
+// balance rotates the taller child of a branch up when it is more than one
+// level taller than the other and returns the branch now in its place
+func (t *DynamicTree) balance(iA int) int {
+	a := &t.nodes[iA]
+	if a.isLeaf() || a.height < 2 {
+		return iA
+	}
+	iB, iC := a.left, a.right
+	b, c := &t.nodes[iB], &t.nodes[iC]
+	switch difference := c.height - b.height; {
+	case difference > 1:
+		//C goes up, A takes the shorter of C's children
+		iF, iG := c.left, c.right
+		f, g := &t.nodes[iF], &t.nodes[iG]
+		t.replaceChild(a.parent, iA, iC)
+		c.left, c.parent, a.parent = iA, a.parent, iC
+		if f.height > g.height {
+			c.right, a.right, g.parent = iF, iG, iA
+			a.bounds, a.height = b.bounds.Union(g.bounds), 1+maxInt(b.height, g.height)
+			c.bounds, c.height = a.bounds.Union(f.bounds), 1+maxInt(a.height, f.height)
+		} else {
+			c.right, a.right, f.parent = iG, iF, iA
+			a.bounds, a.height = b.bounds.Union(f.bounds), 1+maxInt(b.height, f.height)
+			c.bounds, c.height = a.bounds.Union(g.bounds), 1+maxInt(a.height, g.height)
+		}
+		return iC
+	case difference < -1:
+		//B goes up, A takes the shorter of B's children
+		iD, iE := b.left, b.right
+		d, e := &t.nodes[iD], &t.nodes[iE]
+		t.replaceChild(a.parent, iA, iB)
+		b.left, b.parent, a.parent = iA, a.parent, iB
+		if d.height > e.height {
+			b.right, a.left, e.parent = iD, iE, iA
+			a.bounds, a.height = c.bounds.Union(e.bounds), 1+maxInt(c.height, e.height)
+			b.bounds, b.height = a.bounds.Union(d.bounds), 1+maxInt(a.height, d.height)
+		} else {
+			b.right, a.left, d.parent = iE, iD, iA
+			a.bounds, a.height = c.bounds.Union(d.bounds), 1+maxInt(c.height, d.height)
+			b.bounds, b.height = a.bounds.Union(e.bounds), 1+maxInt(a.height, e.height)
+		}
+		return iB
+	}
+	return iA
+}
+
+func (t *DynamicTree) replaceChild(parent, child, replacement int) {
+	if parent == nullNode {
+		t.root = replacement
+	} else if t.nodes[parent].left == child {
+		t.nodes[parent].left = replacement
+	} else {
+		t.nodes[parent].right = replacement
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,227 @@
+package collision
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// validate checks the links, boxes and heights of every branch and that no
+// branch is more than one level out of balance. It returns the leaves
+func (t *DynamicTree) validate(test *testing.T) map[int]bool {
+	test.Helper()
+	leaves := make(map[int]bool)
+	if t.root == nullNode {
+		return leaves
+	}
+	if t.nodes[t.root].parent != nullNode {
+		test.Fatalf("expected the root to have no parent")
+	}
+	stack := []int{t.root}
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &t.nodes[index]
+		if node.isLeaf() {
+			if node.height != 0 {
+				test.Fatalf("node %v: expected a leaf of height 0, got %v", index, node.height)
+			}
+			leaves[index] = true
+			continue
+		}
+		left, right := &t.nodes[node.left], &t.nodes[node.right]
+		if left.parent != index || right.parent != index {
+			test.Fatalf("node %v: expected its children to link back to it", index)
+		}
+		if node.height != 1+maxInt(left.height, right.height) {
+			test.Fatalf("node %v: expected a height of %v, got %v", index, 1+maxInt(left.height, right.height), node.height)
+		}
+		if difference := left.height - right.height; difference > 1 || difference < -1 {
+			test.Fatalf("node %v: expected a balanced branch, got heights %v and %v", index, left.height, right.height)
+		}
+		if node.bounds != left.bounds.Union(right.bounds) {
+			test.Fatalf("node %v: expected its box to fit its children", index)
+		}
+		stack = append(stack, node.left, node.right)
+	}
+	return leaves
+}
+
+func randomBounds(random *rand.Rand, spread float32) AABB {
+	center := mgl32.Vec3{random.Float32(), random.Float32(), random.Float32()}.Mul(spread)
+	size := mgl32.Vec3{random.Float32(), random.Float32(), random.Float32()}.Add(mgl32.Vec3{0.1, 0.1, 0.1})
+	return AABB{center.Sub(size), center.Add(size)}
+}
+
+func TestDynamicTree(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	tree := NewDynamicTree()
+	proxies := make(map[int]AABB)
+	for step := 0; step < 2000; step++ {
+		switch action := random.Intn(4); {
+		case action == 0 && len(proxies) > 0:
+			for proxy := range proxies {
+				tree.Remove(proxy)
+				delete(proxies, proxy)
+				break
+			}
+		case action == 1 && len(proxies) > 0:
+			for proxy, bounds := range proxies {
+				displacement := mgl32.Vec3{random.Float32() - 0.5, random.Float32() - 0.5, random.Float32() - 0.5}
+				moved := AABB{bounds.Min.Add(displacement), bounds.Max.Add(displacement)}
+				tree.Move(proxy, moved, displacement)
+				if !tree.FatBounds(proxy).Contains(moved) {
+					t.Fatalf("expected the fattened box of %v to hold its box", proxy)
+				}
+				proxies[proxy] = moved
+				break
+			}
+		default:
+			bounds := randomBounds(random, 20)
+			proxies[tree.Insert(bounds)] = bounds
+		}
+		if step%100 != 0 {
+			continue
+		}
+		leaves := tree.validate(t)
+		if len(leaves) != len(proxies) {
+			t.Fatalf("step %v: expected %v leaves, got %v", step, len(proxies), len(leaves))
+		}
+		for proxy := range proxies {
+			if !leaves[proxy] {
+				t.Fatalf("step %v: expected proxy %v in the tree", step, proxy)
+			}
+		}
+	}
+
+	//Queries match testing every fattened box
+	query := randomBounds(random, 20).Expand(3)
+	center, radius := mgl32.Vec3{10, 10, 10}, float32(4)
+	frustum := NewFrustum(mgl32.Perspective(mgl32.DegToRad(60), 1, 0.1, 15).Mul4(mgl32.LookAtV(mgl32.Vec3{-5, 10, 10}, mgl32.Vec3{10, 10, 10}, mgl32.Vec3{0, 1, 0})))
+	queries := []struct {
+		name     string
+		query    func(func(int) bool)
+		overlaps func(AABB) bool
+	}{
+		{"aabb", func(callback func(int) bool) { tree.QueryAABB(query, callback) }, query.Overlaps},
+		{"sphere", func(callback func(int) bool) { tree.QuerySphere(center, radius, callback) }, func(bounds AABB) bool { return bounds.OverlapsSphere(center, radius) }},
+		{"frustum", func(callback func(int) bool) { tree.QueryFrustum(&frustum, callback) }, frustum.OverlapsAABB},
+	}
+	for _, q := range queries {
+		found := make(map[int]bool)
+		q.query(func(proxy int) bool {
+			found[proxy] = true
+			return true
+		})
+		expected := 0
+		for proxy := range proxies {
+			if q.overlaps(tree.FatBounds(proxy)) {
+				expected++
+				if !found[proxy] {
+					t.Errorf("%v: expected proxy %v to be found", q.name, proxy)
+				}
+			}
+		}
+		if expected == 0 || len(found) != expected {
+			t.Errorf("%v: expected %v proxies, got %v", q.name, expected, len(found))
+		}
+	}
+}
+
+func TestFrustum(t *testing.T) {
+	frustum := NewFrustum(mgl32.Perspective(mgl32.DegToRad(90), 1, 1, 10).Mul4(mgl32.LookAtV(mgl32.Vec3{}, mgl32.Vec3{0, 0, -1}, mgl32.Vec3{0, 1, 0})))
+	tests := []struct {
+		bounds   AABB
+		expected bool
+	}{
+		{AABB{mgl32.Vec3{-1, -1, -6}, mgl32.Vec3{1, 1, -4}}, true},
+		{AABB{mgl32.Vec3{-1, -1, 4}, mgl32.Vec3{1, 1, 6}}, false},
+		{AABB{mgl32.Vec3{-1, -1, -12}, mgl32.Vec3{1, 1, -11}}, false},
+		{AABB{mgl32.Vec3{5, -1, -3}, mgl32.Vec3{6, 1, -2}}, false},
+		//Straddling the far plane
+		{AABB{mgl32.Vec3{-1, -1, -11}, mgl32.Vec3{1, 1, -9}}, true},
+	}
+	for i, test := range tests {
+		if result := frustum.OverlapsAABB(test.bounds); result != test.expected {
+			t.Errorf("case %v: expected %v, got %v", i, test.expected, result)
+		}
+	}
+}
+
+func TestBroadphasePairs(t *testing.T) {
+	random := rand.New(rand.NewSource(4))
+	broadphase := NewBroadphase()
+	proxies := make(map[int]AABB)
+	pairs := make(map[Pair]bool)
+	for step := 0; step < 200; step++ {
+		for i := 0; i < 3; i++ {
+			bounds := randomBounds(random, 10)
+			proxies[broadphase.CreateProxy(bounds)] = bounds
+		}
+		for proxy, bounds := range proxies {
+			switch random.Intn(8) {
+			case 0:
+				broadphase.DestroyProxy(proxy)
+				delete(proxies, proxy)
+			case 1, 2:
+				displacement := mgl32.Vec3{random.Float32() - 0.5, random.Float32() - 0.5, random.Float32() - 0.5}
+				moved := AABB{bounds.Min.Add(displacement), bounds.Max.Add(displacement)}
+				broadphase.MoveProxy(proxy, moved, displacement)
+				proxies[proxy] = moved
+			}
+		}
+		added, removed := broadphase.UpdatePairs()
+		for _, pair := range removed {
+			delete(pairs, pair)
+		}
+		for _, pair := range added {
+			pairs[pair] = true
+		}
+		//Every overlapping pair is tracked, and no pair of boxes far apart
+		for a := range proxies {
+			for b := range proxies {
+				pair := Pair{a, b}
+				if a >= b {
+					continue
+				}
+				if proxies[a].Overlaps(proxies[b]) && !pairs[pair] {
+					t.Fatalf("step %v: expected the pair %v", step, pair)
+				}
+				if pairs[pair] && !broadphase.Tree.FatBounds(a).Overlaps(broadphase.Tree.FatBounds(b)) {
+					t.Fatalf("step %v: expected the pair %v to be removed", step, pair)
+				}
+			}
+		}
+		if current := broadphase.Pairs(); len(current) != len(pairs) {
+			t.Fatalf("step %v: expected the added and removed pairs to add up to %v pairs, got %v", step, len(current), len(pairs))
+		}
+	}
+}
+
+func TestWorld(t *testing.T) {
+	world := NewWorld()
+	ground := world.Add(Box{mgl32.Vec3{}, mgl32.Vec3{10, 1, 10}})
+	ball := NewTransformed(Sphere{Radius: 0.5}, mgl32.Vec3{0, 5, 0})
+	ballHandle := world.Add(ball)
+	far := world.Add(Sphere{mgl32.Vec3{30, 0, 0}, 1})
+	if contacts := world.Collide(); len(contacts) != 0 {
+		t.Errorf("expected no contacts, got %+v", contacts)
+	}
+	ball.Position = mgl32.Vec3{2, 1.3, 0}
+	world.Update(ballHandle)
+	contacts := world.Collide()
+	if len(contacts) != 1 || contacts[0].A != ground || contacts[0].B != ballHandle || mgl32.Abs(contacts[0].Depth-0.2) > 1e-3 {
+		t.Errorf("expected the ball 0.2 deep in the ground, got %+v", contacts)
+	}
+	if result := world.QuerySphere(mgl32.Vec3{30, 1.5, 0}, 0.6); len(result) != 1 || result[0] != far {
+		t.Errorf("expected the sphere query to find only the far sphere, got %v", result)
+	}
+	if result := world.QueryAABB(AABB{mgl32.Vec3{-1, -1, -1}, mgl32.Vec3{3, 3, 1}}); len(result) != 2 {
+		t.Errorf("expected the ground and the ball, got %v", result)
+	}
+	world.Remove(ground)
+	if contacts := world.Collide(); len(contacts) != 0 {
+		t.Errorf("expected no contacts without the ground, got %+v", contacts)
+	}
+}
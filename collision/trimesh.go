@@ -0,0 +1,210 @@
+package collision
+
+import (
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"training/engine/types"
+)
+
+// Most triangles a leaf of the hierarchy holds
+const bvhLeafSize = 4
+
+// Triangle is a flat convex shape, so a convex shape is tested against the
+// triangles of a mesh with the same GJK and EPA as against any other shape
+type Triangle [3]mgl32.Vec3
+
+func (t Triangle) Support(dir mgl32.Vec3) mgl32.Vec3 {
+	best, bestDot := t[0], t[0].Dot(dir)
+	for i := 1; i < 3; i++ {
+		if temp := t[i].Dot(dir); temp > bestDot {
+			best, bestDot = t[i], temp
+		}
+	}
+	return best
+}
+
+// Normal is the unit normal the counter clockwise winding faces
+func (t Triangle) Normal() mgl32.Vec3 {
+	return unit(t[1].Sub(t[0]).Cross(t[2].Sub(t[0])))
+}
+
+// TriangleContact is a contact with a triangle of a TriangleMesh. Face
+// reports whether it pushes out along the triangle's normal, see Collide
+type TriangleContact struct {
+	Triangle int
+	Face     bool
+	Contact
+}
+
+// bvhNode is a branch with two children, or a leaf holding count triangles
+// from first in the mesh's order when count is not zero
+type bvhNode struct {
+	bounds      AABB
+	left, right int
+	first       int
+	count       int
+}
+
+// TriangleMesh is a static collider for level geometry. Its triangles are
+// sorted into a bounding volume hierarchy, split at the median of the longest
+// axis, so a shape is only tested against the triangles near it
+type TriangleMesh struct {
+	Triangles []Triangle
+	nodes     []bvhNode
+}
+
+// NewTriangleMesh builds the hierarchy, it reorders the triangles
+func NewTriangleMesh(triangles []Triangle) *TriangleMesh {
+	m := &TriangleMesh{Triangles: triangles}
+	if len(triangles) > 0 {
+		m.build(0, len(triangles))
+	}
+	return m
+}
+
+// TrianglesFromMesh reads the triangles of a mesh from its positions, by its
+// indices in threes like collada.ParseMeshSkeleton writes them
+func TrianglesFromMesh(mesh *types.Mesh) []Triangle {
+	//The positions run up to the next block
+	positionCount := len(mesh.Floats)
+	for i := 1; i < len(mesh.Offsets); i++ {
+		if offset := mesh.Offsets[i]; offset > mesh.Offsets[0] && offset < positionCount {
+			positionCount = offset
+		}
+	}
+	positionCount = (positionCount - mesh.Offsets[0]) / 3
+	position := func(vertex uint32) mgl32.Vec3 {
+		start := mesh.Offsets[0] + 3*int(vertex)
+		return mgl32.Vec3{mesh.Floats[start], mesh.Floats[start+1], mesh.Floats[start+2]}
+	}
+	var triangles []Triangle
+	for i := 0; i+2 < len(mesh.Indices); i += 3 {
+		a, b, c := mesh.Indices[i], mesh.Indices[i+1], mesh.Indices[i+2]
+		if int(a) >= positionCount || int(b) >= positionCount || int(c) >= positionCount {
+			continue
+		}
+		triangles = append(triangles, Triangle{position(a), position(b), position(c)})
+	}
+	return triangles
+}
+
+// build sorts the triangles from first to last into a node and returns it
+func (m *TriangleMesh) build(first, last int) int {
+	index := len(m.nodes)
+	m.nodes = append(m.nodes, bvhNode{})
+	bounds := Bounds(m.Triangles[first])
+	centers := AABB{centroid(m.Triangles[first]), centroid(m.Triangles[first])}
+	for i := first + 1; i < last; i++ {
+		bounds = bounds.Union(Bounds(m.Triangles[i]))
+		center := centroid(m.Triangles[i])
+		centers = centers.Union(AABB{center, center})
+	}
+	if last-first <= bvhLeafSize {
+		m.nodes[index] = bvhNode{bounds: bounds, first: first, count: last - first}
+		return index
+	}
+	axis, size := 0, centers.Max.Sub(centers.Min)
+	if size[1] > size[axis] {
+		axis = 1
+	}
+	if size[2] > size[axis] {
+		axis = 2
+	}
+	triangles := m.Triangles[first:last]
+	sort.Slice(triangles, func(i, j int) bool {
+		return centroid(triangles[i])[axis] < centroid(triangles[j])[axis]
+	})
+	middle := (first + last) / 2
+	left := m.build(first, middle)
+	right := m.build(middle, last)
+	m.nodes[index] = bvhNode{bounds: bounds, left: left, right: right}
+	return index
+}
+
+func centroid(t Triangle) mgl32.Vec3 {
+	return t[0].Add(t[1]).Add(t[2]).Mul(1.0 / 3)
+}
+
+// Bounds is the box around every triangle
+func (m *TriangleMesh) Bounds() AABB {
+	if len(m.nodes) == 0 {
+		return AABB{}
+	}
+	return m.nodes[0].bounds
+}
+
+// QueryAABB calls back with every triangle whose box overlaps the box, until
+// the callback returns false
+func (m *TriangleMesh) QueryAABB(bounds AABB, callback func(triangle int) bool) {
+	if len(m.nodes) == 0 {
+		return
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &m.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !node.bounds.Overlaps(bounds) {
+			continue
+		}
+		if node.count == 0 {
+			stack = append(stack, node.left, node.right)
+			continue
+		}
+		for i := node.first; i < node.first+node.count; i++ {
+			if Bounds(m.Triangles[i]).Overlaps(bounds) && !callback(i) {
+				return
+			}
+		}
+	}
+}
+
+// Collide tests a convex shape against the triangles near it. The shape is
+// shape A of every contact, so moving it by Normal*Depth separates it from
+// that triangle. A shape whose centre is above the face of a triangle is
+// pushed out along the triangle's normal on the side of its centre, EPA
+// alone would push it sideways over the edges between the triangles of a
+// flat floor, or through thin walls
+func (m *TriangleMesh) Collide(shape Shape) []TriangleContact {
+	var contacts []TriangleContact
+	bounds := Bounds(shape)
+	center := bounds.Min.Add(bounds.Max).Mul(0.5)
+	m.QueryAABB(bounds, func(index int) bool {
+		triangle := m.Triangles[index]
+		contact, ok := Penetration(shape, triangle)
+		if !ok || contact.Depth <= 0 {
+			return true
+		}
+		result := TriangleContact{Triangle: index, Contact: contact}
+		if normal, over := triangle.faceNormal(center); over {
+			deepest := shape.Support(normal.Mul(-1))
+			if depth := normal.Dot(triangle[0].Sub(deepest)); depth > 0 {
+				result.Contact = Contact{
+					Depth:  depth,
+					Normal: normal,
+					PointA: deepest,
+					PointB: deepest.Add(normal.Mul(depth)),
+				}
+				result.Face = true
+			}
+		}
+		contacts = append(contacts, result)
+		return true
+	})
+	return contacts
+}
+
+// faceNormal is the normal of the triangle facing the point, it reports
+// whether the point lies above the face rather than beside it
+func (t Triangle) faceNormal(point mgl32.Vec3) (mgl32.Vec3, bool) {
+	normal := t.Normal()
+	if normal.Len() == 0 {
+		return normal, false
+	}
+	if normal.Dot(point.Sub(t[0])) < 0 {
+		normal = normal.Mul(-1)
+	}
+	projection := point.Sub(normal.Mul(normal.Dot(point.Sub(t[0]))))
+	u, v, w := barycentric(projection, t[0], t[1], t[2])
+	return normal, u >= 0 && v >= 0 && w >= 0
+}
@@ -0,0 +1,127 @@
+package collision
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"training/engine/types"
+)
+
+// floor is a grid of size by size unit quads at height 0, split into
+// triangles
+func floor(size int) []Triangle {
+	var triangles []Triangle
+	for x := 0; x < size; x++ {
+		for z := 0; z < size; z++ {
+			a := mgl32.Vec3{float32(x), 0, float32(z)}
+			b, c, d := a.Add(mgl32.Vec3{1, 0, 0}), a.Add(mgl32.Vec3{1, 0, 1}), a.Add(mgl32.Vec3{0, 0, 1})
+			triangles = append(triangles, Triangle{a, d, c}, Triangle{a, c, b})
+		}
+	}
+	return triangles
+}
+
+func TestTriangleMeshQuery(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	var triangles []Triangle
+	for i := 0; i < 500; i++ {
+		corner := mgl32.Vec3{random.Float32(), random.Float32(), random.Float32()}.Mul(50)
+		triangles = append(triangles, Triangle{corner, corner.Add(mgl32.Vec3{random.Float32(), 0, 1}), corner.Add(mgl32.Vec3{0, random.Float32(), 1})})
+	}
+	mesh := NewTriangleMesh(triangles)
+	if len(mesh.Triangles) != 500 {
+		t.Fatalf("expected 500 triangles, got %v", len(mesh.Triangles))
+	}
+	for i := 0; i < 20; i++ {
+		query := randomBounds(random, 50).Expand(3)
+		found := make(map[int]bool)
+		mesh.QueryAABB(query, func(triangle int) bool {
+			found[triangle] = true
+			return true
+		})
+		expected := 0
+		for triangle := range mesh.Triangles {
+			if Bounds(mesh.Triangles[triangle]).Overlaps(query) {
+				expected++
+				if !found[triangle] {
+					t.Errorf("query %v: expected triangle %v to be found", i, triangle)
+				}
+			}
+		}
+		if len(found) != expected {
+			t.Errorf("query %v: expected %v triangles, got %v", i, expected, len(found))
+		}
+	}
+}
+
+func TestTriangleMeshCollide(t *testing.T) {
+	mesh := NewTriangleMesh(floor(10))
+	//A capsule sunk into the floor right over the edges between the quads
+	capsule := Capsule{mgl32.Vec3{3, 0.3, 3}, mgl32.Vec3{3, 1.5, 3}, 0.4}
+	contacts := mesh.Collide(capsule)
+	if len(contacts) == 0 {
+		t.Fatal("expected the capsule to touch the floor")
+	}
+	faces := 0
+	for _, contact := range contacts {
+		if !contact.Face {
+			continue
+		}
+		faces++
+		if mgl32.Abs(contact.Depth-0.1) > 1e-3 || contact.Normal.Sub(mgl32.Vec3{0, 1, 0}).Len() > 1e-3 {
+			t.Errorf("expected the floor to push the capsule up by 0.1, got %+v", contact)
+		}
+	}
+	if faces == 0 {
+		t.Errorf("expected the capsule to be over the face of a triangle, got %+v", contacts)
+	}
+	//Pushed out by the deepest face contact, the capsule rests on the floor
+	capsule.A[1] += 0.1
+	capsule.B[1] += 0.1
+	for _, contact := range mesh.Collide(capsule) {
+		if contact.Depth > 1e-3 {
+			t.Errorf("expected the capsule to rest on the floor, got %+v", contact)
+		}
+	}
+	if contacts := mesh.Collide(Sphere{mgl32.Vec3{5, 2, 5}, 1}); len(contacts) != 0 {
+		t.Errorf("expected no contacts above the floor, got %+v", contacts)
+	}
+
+	//Beside the floor the box is pushed off the edge
+	box := &Transformed{Box{mgl32.Vec3{}, mgl32.Vec3{0.5, 0.5, 0.5}}, mgl32.Vec3{10.3, -0.45, 5.2}, mgl32.QuatIdent()}
+	contacts = mesh.Collide(box)
+	if len(contacts) == 0 {
+		t.Fatal("expected the box to touch the edge of the floor")
+	}
+	for _, contact := range contacts {
+		if contact.Face || contact.Normal.Sub(mgl32.Vec3{0, -1, 0}).Len() > 1e-3 || mgl32.Abs(contact.Depth-0.05) > 1e-3 {
+			t.Errorf("expected the edge of the floor to push the box down by 0.05, got %+v", contact)
+		}
+	}
+}
+
+func TestTrianglesFromMesh(t *testing.T) {
+	//Positions followed by normals, with the last triangle out of range
+	mesh := &types.Mesh{
+		Floats: []float32{
+			0, 0, 0, 1, 0, 0, 0, 0, 1, 1, 0, 1,
+			0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1, 0,
+		},
+		Indices: []uint32{0, 2, 1, 1, 2, 3, 1, 2, 4},
+		Offsets: [6]int{0, 12, 24, 24, 24, 24},
+	}
+	triangles := TrianglesFromMesh(mesh)
+	expected := []Triangle{
+		{{0, 0, 0}, {0, 0, 1}, {1, 0, 0}},
+		{{1, 0, 0}, {0, 0, 1}, {1, 0, 1}},
+	}
+	if len(triangles) != len(expected) {
+		t.Fatalf("expected %v triangles, got %v", len(expected), triangles)
+	}
+	for i := range expected {
+		if triangles[i] != expected[i] {
+			t.Errorf("triangle %v: expected %v, got %v", i, expected[i], triangles[i])
+		}
+	}
+}
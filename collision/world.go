@@ -0,0 +1,109 @@
+package collision
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// WorldContact is a contact between two colliders of a World
+type WorldContact struct {
+	A, B int
+	Contact
+}
+
+type collider struct {
+	shape  Shape
+	bounds AABB
+}
+
+// World holds colliders in a Broadphase and only runs GJK and EPA on the
+// pairs whose boxes overlap. Colliders are referred to by their proxies
+type World struct {
+	Broadphase *Broadphase
+	colliders  map[int]*collider
+}
+
+func NewWorld() *World {
+	return &World{Broadphase: NewBroadphase(), colliders: make(map[int]*collider)}
+}
+
+// Add puts a shape in the world and returns its handle
+func (w *World) Add(shape Shape) int {
+	bounds := Bounds(shape)
+	handle := w.Broadphase.CreateProxy(bounds)
+	w.colliders[handle] = &collider{shape, bounds}
+	return handle
+}
+
+// Remove takes a collider out of the world
+func (w *World) Remove(handle int) {
+	w.Broadphase.DestroyProxy(handle)
+	delete(w.colliders, handle)
+}
+
+// Shape returns the shape of a collider
+func (w *World) Shape(handle int) Shape {
+	return w.colliders[handle].shape
+}
+
+// Update has to be called after the shape of a collider moved, like when the
+// Position of a Transformed changed
+func (w *World) Update(handle int) {
+	c := w.colliders[handle]
+	bounds := Bounds(c.shape)
+	displacement := bounds.Min.Add(bounds.Max).Sub(c.bounds.Min.Add(c.bounds.Max)).Mul(0.5)
+	c.bounds = bounds
+	w.Broadphase.MoveProxy(handle, bounds, displacement)
+}
+
+// Collide updates the pairs of the broadphase and returns the contacts of the
+// ones that overlap
+func (w *World) Collide() []WorldContact {
+	w.Broadphase.UpdatePairs()
+	var contacts []WorldContact
+	for _, pair := range w.Broadphase.Pairs() {
+		a, b := w.colliders[pair.A], w.colliders[pair.B]
+		if !a.bounds.Overlaps(b.bounds) {
+			continue
+		}
+		if contact, ok := Penetration(a.shape, b.shape); ok {
+			contacts = append(contacts, WorldContact{pair.A, pair.B, contact})
+		}
+	}
+	return contacts
+}
+
+// QueryAABB returns the colliders whose boxes overlap the box
+func (w *World) QueryAABB(bounds AABB) []int {
+	var result []int
+	w.Broadphase.Tree.QueryAABB(bounds, func(handle int) bool {
+		if w.colliders[handle].bounds.Overlaps(bounds) {
+			result = append(result, handle)
+		}
+		return true
+	})
+	return result
+}
+
+// QuerySphere returns the colliders that overlap the sphere
+func (w *World) QuerySphere(center mgl32.Vec3, radius float32) []int {
+	var result []int
+	sphere := Sphere{center, radius}
+	w.Broadphase.Tree.QuerySphere(center, radius, func(handle int) bool {
+		if _, separated := Distance(w.colliders[handle].shape, sphere, epaTolerance); !separated {
+			result = append(result, handle)
+		}
+		return true
+	})
+	return result
+}
+
+// QueryFrustum returns the colliders whose boxes are at least partly inside
+// the frustum
+func (w *World) QueryFrustum(frustum *Frustum) []int {
+	var result []int
+	w.Broadphase.Tree.QueryFrustum(frustum, func(handle int) bool {
+		if frustum.OverlapsAABB(w.colliders[handle].bounds) {
+			result = append(result, handle)
+		}
+		return true
+	})
+	return result
+}
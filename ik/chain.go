@@ -0,0 +1,66 @@
+package ik
+
+import (
+	"fmt"
+	"training/engine/anim"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Chain solves a chain of any length with cyclic coordinate descent, turning
+// one joint at a time from the end towards the root until the last bone's
+// head is within Tolerance of Target
+type Chain struct {
+	Bones      []int
+	Target     mgl32.Vec3
+	Iterations int
+	Tolerance  float32
+	Weight     float32
+	subtrees   [][]int
+	original   []mgl32.Mat4
+}
+
+// NewChain builds a solver for the bones from root down to end
+func NewChain(skeleton *anim.Skeleton, root, end string) (*Chain, error) {
+	rootIndex, err := boneIndex(skeleton, root)
+	if err != nil {
+		return nil, err
+	}
+	endIndex, err := boneIndex(skeleton, end)
+	if err != nil {
+		return nil, err
+	}
+	bones := []int{endIndex}
+	for bones[0] != rootIndex {
+		if bones[0] == skeleton.RootIndex {
+			return nil, fmt.Errorf("ik: bone %v is not a descendant of %v", end, root)
+		}
+		bones = append([]int{skeleton.Bones[bones[0]].ParentIndex}, bones...)
+	}
+	if len(bones) < 2 {
+		return nil, fmt.Errorf("ik: chain from %v to %v needs at least two bones", root, end)
+	}
+	chain := &Chain{Bones: bones, Iterations: 10, Tolerance: 1e-3, Weight: 1}
+	for _, bone := range bones {
+		chain.subtrees = append(chain.subtrees, skeleton.Subtree(bone))
+	}
+	return chain, nil
+}
+
+func (c *Chain) Apply(a *anim.Animator) {
+	if c.Weight <= 0 {
+		return
+	}
+	c.original = snapshot(a, c.subtrees[0], c.original)
+	end := c.Bones[len(c.Bones)-1]
+	for iteration := 0; iteration < c.Iterations; iteration++ {
+		if joint(a, end).Sub(c.Target).Len() <= c.Tolerance {
+			break
+		}
+		for i := len(c.Bones) - 2; i >= 0; i-- {
+			pivot := joint(a, c.Bones[i])
+			rotate(a, c.subtrees[i], pivot, between(joint(a, end).Sub(pivot), c.Target.Sub(pivot)))
+		}
+	}
+	blend(a, c.subtrees[0], c.original, c.Weight)
+}
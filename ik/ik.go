@@ -0,0 +1,119 @@
+// Package ik adjusts the pose computed by an anim.Animator so that it reaches
+// targets in the world. The solvers work on the animator's global pose
+// matrices in model space and run as anim.PostProcessor steps, each with a
+// weight that blends between the animated and the solved pose.
+package ik
+
+import (
+	"fmt"
+	"math"
+	"training/engine/anim"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// joint returns the model space position of a bone's head in the current pose
+func joint(a *anim.Animator, bone int) mgl32.Vec3 {
+	return a.GlobalPoseMatrices[bone].Mul4x1(a.Skeleton().Bones[bone].BindPose.Col(3)).Vec3()
+}
+
+// rotate turns the bones around pivot by rotation
+func rotate(a *anim.Animator, bones []int, pivot mgl32.Vec3, rotation mgl32.Quat) {
+	m := mgl32.Translate3D(pivot[0], pivot[1], pivot[2]).Mul4(rotation.Mat4()).Mul4(mgl32.Translate3D(-pivot[0], -pivot[1], -pivot[2]))
+	for _, bone := range bones {
+		a.GlobalPoseMatrices[bone] = m.Mul4(a.GlobalPoseMatrices[bone])
+	}
+}
+
+// between returns the rotation from one direction to another, the identity
+// when either of them has no length
+func between(from, to mgl32.Vec3) mgl32.Quat {
+	if from.Len() < 1e-6 || to.Len() < 1e-6 {
+		return mgl32.QuatIdent()
+	}
+	return mgl32.QuatBetweenVectors(from, to)
+}
+
+func snapshot(a *anim.Animator, bones []int, buffer []mgl32.Mat4) []mgl32.Mat4 {
+	buffer = buffer[:0]
+	for _, bone := range bones {
+		buffer = append(buffer, a.GlobalPoseMatrices[bone])
+	}
+	return buffer
+}
+
+// blend moves the solved bones back towards their original matrices. The
+// bones, listed parent first like anim.Skeleton.Subtree returns them, are
+// blended in the local space of their joints and rebuilt from their parents,
+// so at any weight every joint stays at the end of its parent bone
+func blend(a *anim.Animator, bones []int, original []mgl32.Mat4, weight float32) {
+	if weight >= 1 {
+		return
+	}
+	skeleton := a.Skeleton()
+	//Children first, so the solved matrix of a bone's parent is still in place
+	//when the bone's matrix is replaced by its blended local one
+	for i := len(bones) - 1; i >= 0; i-- {
+		bone := bones[i]
+		bindPose := skeleton.Bones[bone].BindPose
+		originalParent, solvedParent := parentJoints(a, bones[:i], original, bone)
+		first := anim.Mat4ToTransform(originalParent.Inv().Mul4(original[i].Mul4(bindPose)))
+		second := anim.Mat4ToTransform(solvedParent.Inv().Mul4(a.GlobalPoseMatrices[bone].Mul4(bindPose)))
+		if first.Rotation.Dot(second.Rotation) < 0 {
+			second.Rotation = second.Rotation.Scale(-1)
+		}
+		result := anim.Transform{Rotation: mgl32.QuatSlerp(first.Rotation, second.Rotation, weight)}
+		for j := 0; j < 3; j++ {
+			result.Translate[j] = first.Translate[j]*(1-weight) + second.Translate[j]*weight
+			result.Scale[j] = first.Scale[j]*(1-weight) + second.Scale[j]*weight
+		}
+		a.GlobalPoseMatrices[bone] = anim.TransformToMat4(result)
+	}
+	for _, bone := range bones {
+		parent := mgl32.Ident4()
+		if bone != skeleton.RootIndex {
+			parentIndex := skeleton.Bones[bone].ParentIndex
+			parent = a.GlobalPoseMatrices[parentIndex].Mul4(skeleton.Bones[parentIndex].BindPose)
+		}
+		a.GlobalPoseMatrices[bone] = parent.Mul4(a.GlobalPoseMatrices[bone]).Mul4(skeleton.Bones[bone].InverseBindPose)
+	}
+}
+
+// parentJoints returns the model space joint matrix of a bone's parent before
+// and after solving, parents that are not among the solved bones did not move
+func parentJoints(a *anim.Animator, solved []int, original []mgl32.Mat4, bone int) (mgl32.Mat4, mgl32.Mat4) {
+	skeleton := a.Skeleton()
+	if bone == skeleton.RootIndex {
+		return mgl32.Ident4(), mgl32.Ident4()
+	}
+	parent := skeleton.Bones[bone].ParentIndex
+	bindPose := skeleton.Bones[parent].BindPose
+	current := a.GlobalPoseMatrices[parent].Mul4(bindPose)
+	for i, solvedBone := range solved {
+		if solvedBone == parent {
+			return original[i].Mul4(bindPose), current
+		}
+	}
+	return current, current
+}
+
+func boneIndex(skeleton *anim.Skeleton, name string) (int, error) {
+	bone := skeleton.BoneIndex(name)
+	if bone < 0 {
+		return 0, fmt.Errorf("ik: unknown bone %v", name)
+	}
+	return bone, nil
+}
+
+func angleBetween(u, v mgl32.Vec3) float32 {
+	return float32(math.Acos(float64(mgl32.Clamp(u.Normalize().Dot(v.Normalize()), -1, 1))))
+}
+
+// anyPerpendicular returns a unit vector perpendicular to v
+func anyPerpendicular(v mgl32.Vec3) mgl32.Vec3 {
+	perpendicular := v.Cross(mgl32.Vec3{1, 0, 0})
+	if perpendicular.Len() < 1e-3*v.Len() {
+		perpendicular = v.Cross(mgl32.Vec3{0, 1, 0})
+	}
+	return perpendicular.Normalize()
+}
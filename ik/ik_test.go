@@ -0,0 +1,134 @@
+package ik
+
+import (
+	"math"
+	"testing"
+	"training/engine/anim"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// straightArm returns an animator holding a chain of bones one unit apart
+// along y, named a, b, c...
+func straightArm(t *testing.T, boneCount int) *anim.Animator {
+	skeleton := &anim.Skeleton{Bones: make([]anim.Bone, boneCount)}
+	rest := anim.Keyframe{Transforms: make([]anim.Transform, boneCount)}
+	for i := range skeleton.Bones {
+		bindPose := mgl32.Translate3D(0, float32(i), 0)
+		skeleton.Bones[i] = anim.Bone{Name: string(rune('a' + i)), BindPose: bindPose, InverseBindPose: bindPose.Inv(), ParentIndex: i - 1, Index: i}
+		rest.Transforms[i] = anim.IdentityTransform()
+	}
+	animator, err := anim.NewAnimator(skeleton, []anim.Animation{{Name: "rest", Keyframes: []anim.Keyframe{rest}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animator.SetBlendTree(&anim.ClipNode{Animation: 0})
+	return animator
+}
+
+func TestTwoBone(t *testing.T) {
+	animator := straightArm(t, 4)
+	solver, err := NewTwoBone(animator.Skeleton(), "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	solver.Target = mgl32.Vec3{1, 1, 0}
+	solver.Pole = mgl32.Vec3{0, 2, 1}
+	animator.AddPostProcessor(solver)
+	animator.Update(0.1)
+	if end := joint(animator, 3); !end.ApproxEqualThreshold(solver.Target, 1e-4) {
+		t.Errorf("expected the end at %v, got %v", solver.Target, end)
+	}
+	//Bone lengths are kept and the middle joint bends towards the pole
+	if middle := joint(animator, 2); !mgl32.FloatEqualThreshold(middle.Sub(joint(animator, 1)).Len(), 1, 1e-4) || middle[2] <= 0 {
+		t.Errorf("unexpected middle joint %v", middle)
+	}
+	if root := joint(animator, 0); !root.ApproxEqual(mgl32.Vec3{}) {
+		t.Errorf("expected the bones above the limb to stay, got %v", root)
+	}
+
+	solver.Target = mgl32.Vec3{0, 10, 0}
+	solver.Weight = 0.5
+	animator.Update(0.1)
+	if end := joint(animator, 3); !end.ApproxEqualThreshold(mgl32.Vec3{0, 3, 0}, 1e-3) {
+		t.Errorf("expected an unreachable target to leave the arm stretched, got %v", end)
+	}
+	if _, err := NewTwoBone(animator.Skeleton(), "b"); err == nil {
+		t.Errorf("expected an error for a limb without two parents")
+	}
+}
+
+func TestChain(t *testing.T) {
+	animator := straightArm(t, 5)
+	solver, err := NewChain(animator.Skeleton(), "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	solver.Target = mgl32.Vec3{2, 1, 1}
+	solver.Iterations = 50
+	animator.AddPostProcessor(solver)
+	animator.Update(0.1)
+	if end := joint(animator, 4); end.Sub(solver.Target).Len() > 1e-2 {
+		t.Errorf("expected the chain to reach %v, got %v", solver.Target, end)
+	}
+	for i := 1; i < 5; i++ {
+		if length := joint(animator, i).Sub(joint(animator, i-1)).Len(); !mgl32.FloatEqualThreshold(length, 1, 1e-4) {
+			t.Errorf("bone %v changed length to %v", i, length)
+		}
+	}
+	if _, err := NewChain(animator.Skeleton(), "c", "b"); err == nil {
+		t.Errorf("expected an error for an end above the root")
+	}
+}
+
+func TestLookAt(t *testing.T) {
+	animator := straightArm(t, 3)
+	solver, err := NewLookAt(animator.Skeleton(), "b", mgl32.Vec3{0, 0, 1}, mgl32.DegToRad(45))
+	if err != nil {
+		t.Fatal(err)
+	}
+	solver.Target = mgl32.Vec3{10, 1, 0}
+	animator.AddPostProcessor(solver)
+	animator.Update(0.1)
+	forward := animator.GlobalPoseMatrices[1].Mul4x1(mgl32.Vec4{0, 0, 1, 0}).Vec3()
+	//The target is 90 degrees to the side, the limit stops the turn halfway
+	if expected := (mgl32.Vec3{1, 0, 1}).Normalize(); !forward.ApproxEqualThreshold(expected, 1e-4) {
+		t.Errorf("expected to face %v, got %v", expected, forward)
+	}
+	if head := joint(animator, 1); !head.ApproxEqual(mgl32.Vec3{0, 1, 0}) {
+		t.Errorf("expected the bone to turn in place, got %v", head)
+	}
+	solver.MaxAngle = 0
+	solver.Weight = 0.5
+	animator.Update(0.1)
+	forward = animator.GlobalPoseMatrices[1].Mul4x1(mgl32.Vec4{0, 0, 1, 0}).Vec3()
+	if angle := math.Atan2(float64(forward[0]), float64(forward[2])); !mgl32.FloatEqualThreshold(float32(angle), mgl32.DegToRad(45), 1e-4) {
+		t.Errorf("expected half of the turn at half weight, got %v", mgl32.RadToDeg(float32(angle)))
+	}
+}
+
+func TestBlendKeepsBoneLengths(t *testing.T) {
+	animator := straightArm(t, 5)
+	twoBone, err := NewTwoBone(animator.Skeleton(), "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	twoBone.Target, twoBone.Pole, twoBone.Weight = mgl32.Vec3{1, 3, 0}, mgl32.Vec3{0, 3, 1}, 0.5
+	chain, err := NewChain(animator.Skeleton(), "a", "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Target, chain.Iterations, chain.Weight = mgl32.Vec3{1.5, 1, 0.5}, 50, 0.5
+	animator.AddPostProcessor(chain)
+	animator.AddPostProcessor(twoBone)
+	animator.Update(0.1)
+	//Halfway between the animated and the solved pose no joint leaves its bone
+	for i := 1; i < 5; i++ {
+		if length := joint(animator, i).Sub(joint(animator, i-1)).Len(); !mgl32.FloatEqualThreshold(length, 1, 1e-4) {
+			t.Errorf("bone %v changed length to %v", i, length)
+		}
+	}
+	if end := joint(animator, 4); end.Sub(mgl32.Vec3{0, 4, 0}).Len() < 0.1 || end.Sub(twoBone.Target).Len() < 1e-2 {
+		t.Errorf("expected the end between the animated pose and the target, got %v", end)
+	}
+}
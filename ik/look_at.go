@@ -0,0 +1,48 @@
+package ik
+
+import (
+	"training/engine/anim"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// LookAt turns a bone, like the head or a gun arm, so that its Forward axis
+// points at Target. Forward is the direction the bone faces in the bind pose,
+// in model space, and the bone turns at most MaxAngle radians away from its
+// animated direction, zero meaning no limit
+type LookAt struct {
+	Bone     int
+	Forward  mgl32.Vec3
+	Target   mgl32.Vec3
+	MaxAngle float32
+	Weight   float32
+	bones    []int
+	original []mgl32.Mat4
+}
+
+func NewLookAt(skeleton *anim.Skeleton, bone string, forward mgl32.Vec3, maxAngle float32) (*LookAt, error) {
+	index, err := boneIndex(skeleton, bone)
+	if err != nil {
+		return nil, err
+	}
+	return &LookAt{Bone: index, Forward: forward, MaxAngle: maxAngle, Weight: 1, bones: skeleton.Subtree(index)}, nil
+}
+
+func (l *LookAt) Apply(a *anim.Animator) {
+	if l.Weight <= 0 {
+		return
+	}
+	l.original = snapshot(a, l.bones, l.original)
+	pivot := joint(a, l.Bone)
+	forward := a.GlobalPoseMatrices[l.Bone].Mul4x1(l.Forward.Vec4(0)).Vec3()
+	desired := l.Target.Sub(pivot)
+	if forward.Len() < 1e-6 || desired.Len() < 1e-6 {
+		return
+	}
+	rotation := between(forward, desired)
+	if angle := angleBetween(forward, desired); l.MaxAngle > 0 && angle > l.MaxAngle {
+		rotation = mgl32.QuatSlerp(mgl32.QuatIdent(), rotation, l.MaxAngle/angle)
+	}
+	rotate(a, l.bones, pivot, rotation)
+	blend(a, l.bones, l.original, l.Weight)
+}
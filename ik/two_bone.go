@@ -0,0 +1,83 @@
+package ik
+
+import (
+	"fmt"
+	"math"
+	"training/engine/anim"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// TwoBone solves a limb of two bones analytically, like a leg from hip to
+// ankle. The middle joint bends towards Pole, Target and Pole are in model
+// space
+type TwoBone struct {
+	Upper, Lower, End int
+	Target, Pole      mgl32.Vec3
+	Weight            float32
+	upperBones        []int
+	lowerBones        []int
+	original          []mgl32.Mat4
+}
+
+// NewTwoBone builds a solver for the limb that ends at the named bone, its two
+// parents being the lower and upper bones
+func NewTwoBone(skeleton *anim.Skeleton, end string) (*TwoBone, error) {
+	endIndex, err := boneIndex(skeleton, end)
+	if err != nil {
+		return nil, err
+	}
+	lower := skeleton.Bones[endIndex].ParentIndex
+	if endIndex == skeleton.RootIndex || lower == skeleton.RootIndex {
+		return nil, fmt.Errorf("ik: bone %v needs two parents", end)
+	}
+	upper := skeleton.Bones[lower].ParentIndex
+	return &TwoBone{Upper: upper, Lower: lower, End: endIndex, Weight: 1, upperBones: skeleton.Subtree(upper), lowerBones: skeleton.Subtree(lower)}, nil
+}
+
+func (s *TwoBone) Apply(a *anim.Animator) {
+	if s.Weight <= 0 {
+		return
+	}
+	s.original = snapshot(a, s.upperBones, s.original)
+	root, middle, end := joint(a, s.Upper), joint(a, s.Lower), joint(a, s.End)
+	toTarget := s.Target.Sub(root)
+	if toTarget.Len() < 1e-6 {
+		return
+	}
+	upperLength, lowerLength := middle.Sub(root).Len(), end.Sub(middle).Len()
+	//Targets out of reach stretch the limb towards them
+	distance := mgl32.Clamp(toTarget.Len(), float32(math.Abs(float64(upperLength-lowerLength))), upperLength+lowerLength)
+
+	//Bend the middle joint until the end is as far from the root as the target
+	u, v := root.Sub(middle), end.Sub(middle)
+	axis := u.Cross(v)
+	if axis.Len() < 1e-6 {
+		//A straight limb bends towards the pole
+		if axis = u.Cross(s.Pole.Sub(middle)); axis.Len() < 1e-6 {
+			axis = anyPerpendicular(u)
+		}
+	}
+	cosine := (upperLength*upperLength + lowerLength*lowerLength - distance*distance) / (2 * upperLength * lowerLength)
+	desired := float32(math.Acos(float64(mgl32.Clamp(cosine, -1, 1))))
+	rotate(a, s.lowerBones, middle, mgl32.QuatRotate(desired-angleBetween(u, v), axis.Normalize()))
+
+	//Swing the limb towards the target
+	end = joint(a, s.End)
+	rotate(a, s.upperBones, root, between(end.Sub(root), toTarget))
+
+	//Twist around the root to target axis until the middle joint faces the pole
+	direction := toTarget.Normalize()
+	middle = joint(a, s.Lower)
+	m, p := reject(middle.Sub(root), direction), reject(s.Pole.Sub(root), direction)
+	if m.Len() > 1e-6 && p.Len() > 1e-6 {
+		twist := float32(math.Atan2(float64(direction.Dot(m.Cross(p))), float64(m.Dot(p))))
+		rotate(a, s.upperBones, root, mgl32.QuatRotate(twist, direction))
+	}
+	blend(a, s.upperBones, s.original, s.Weight)
+}
+
+// reject removes the component of v along the unit vector axis
+func reject(v, axis mgl32.Vec3) mgl32.Vec3 {
+	return v.Sub(axis.Mul(v.Dot(axis)))
+}
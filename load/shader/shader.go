@@ -10,29 +10,37 @@ import (
 )
 
 func NewProgram(fileName string) (uint32, error) {
+	return NewProgramFromFiles(fileName, fileName)
+}
+
+// NewProgramFromFiles links a vertex and a fragment shader read from different
+// files, so several vertex shaders can share one fragment shader. Each define
+// like "MAX_BONES 64" is added to both sources as a #define after the #version
+// line, to specialize a shader without editing its file
+func NewProgramFromFiles(vertexFileName, fragmentFileName string, defines ...string) (uint32, error) {
 	workingDirectory, err := os.Getwd()
 	if err != nil {
 		return 0, fmt.Errorf("shader: realative path read error: %v", err)
 	}
 
 	//Read vertex shader
-	absolutePath := workingDirectory + "/shaders/" + fileName + ".vert"
+	absolutePath := workingDirectory + "/shaders/" + vertexFileName + ".vert"
 	vertexSourceBytes, err := ioutil.ReadFile(absolutePath)
 	if err != nil {
 		return 0, fmt.Errorf("shader: vertex file read error: %v", err)
 	}
-	vertexShader, err := compileShader(string(vertexSourceBytes)+"\x00", gl.VERTEX_SHADER)
+	vertexShader, err := compileShader(addDefines(string(vertexSourceBytes), defines)+"\x00", gl.VERTEX_SHADER)
 	if err != nil {
 		return 0, fmt.Errorf("shader: file %v vertex shader compilation error:\n%v", absolutePath, err)
 	}
 
 	//Read fragment shader
-	absolutePath = workingDirectory + "/shaders/" + fileName + ".frag"
+	absolutePath = workingDirectory + "/shaders/" + fragmentFileName + ".frag"
 	fragmentSourceBytes, err := ioutil.ReadFile(absolutePath)
 	if err != nil {
 		return 0, fmt.Errorf("shader: fragment file read error: %v", err)
 	}
-	fragmentShader, err := compileShader(string(fragmentSourceBytes)+"\x00", gl.FRAGMENT_SHADER)
+	fragmentShader, err := compileShader(addDefines(string(fragmentSourceBytes), defines)+"\x00", gl.FRAGMENT_SHADER)
 	if err != nil {
 		return 0, fmt.Errorf("shader: file %v fragment shader compilation error:\n%v", absolutePath, err)
 	}
@@ -61,6 +69,23 @@ func NewProgram(fileName string) (uint32, error) {
 	return program, nil
 }
 
+// addDefines inserts the defines after the #version line, which has to stay
+// the first statement of the source
+func addDefines(source string, defines []string) string {
+	if len(defines) == 0 {
+		return source
+	}
+	lines := "#define " + strings.Join(defines, "\n#define ") + "\n"
+	if strings.HasPrefix(strings.TrimSpace(source), "#version") {
+		start := strings.Index(source, "#version")
+		if end := strings.Index(source[start:], "\n"); end >= 0 {
+			return source[:start+end+1] + lines + source[start+end+1:]
+		}
+		return source + "\n" + lines
+	}
+	return lines + source
+}
+
 func compileShader(source string, shaderType uint32) (uint32, error) {
 	shader := gl.CreateShader(shaderType)
 
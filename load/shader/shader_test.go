@@ -0,0 +1,14 @@
+package shader
+
+import "testing"
+
+func TestAddDefines(t *testing.T) {
+	source := "#version 330\n\nuniform mat4 vp_mat;\n"
+	expected := "#version 330\n#define MAX_BONES 40\n#define DUAL_QUATERNION\n\nuniform mat4 vp_mat;\n"
+	if result := addDefines(source, []string{"MAX_BONES 40", "DUAL_QUATERNION"}); result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+	if result := addDefines(source, nil); result != source {
+		t.Errorf("expected the source unchanged, got %q", result)
+	}
+}
@@ -66,25 +66,62 @@ func main() {
 		window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
 
 		//Load player data
-		mesh, skeleton, err0, err1 := collada.ParseMeshSkeleton("data/model/turner1.dae")
+		mesh, skeleton, importedAnimations, err0, err1 := collada.ParseMeshSkeletonAnimations("data/model/turner1.dae")
 		if err0 != nil {
 			log.Fatalln(err0)
 		} else if err1 != nil {
 			log.Fatalln(err1)
 		}
 		model := types.Model{Mesh: mesh}
-		model.Animator, err = anim.NewAnimator(skeleton, []anim.Animation{anim.Animation{Duration: keyframe04.SampleTime, Keyframes: []anim.Keyframe{keyframe00, keyframe01, keyframe02, keyframe03, keyframe04}},
-			anim.Animation{Duration: keyframe14.SampleTime, Keyframes: []anim.Keyframe{keyframe10, keyframe11, keyframe12, keyframe13, keyframe14}},
-			anim.Animation{Duration: keyframe21.SampleTime, Keyframes: []anim.Keyframe{keyframe20, keyframe21}},
-			anim.Animation{Duration: keyframe32.SampleTime, Keyframes: []anim.Keyframe{keyframe30, keyframe31, keyframe32}},
-			anim.Animation{Duration: keyframe41.SampleTime, Keyframes: []anim.Keyframe{keyframe40, keyframe41}}})
+		//The head turn only reaches the bones it animates, without them the player plays on without the layer
+		headTurn := anim.Animation{Name: "head turn", Duration: keyframe21.SampleTime, Keyframes: []anim.Keyframe{keyframe20, keyframe21}}
+		headMask, err := skeleton.AddAnimationMask("head turn", &headTurn, 1)
+		if err != nil {
+			log.Println(err)
+		}
+		//Clips exported with the model are appended after the hand authored ones
+		model.Animator, err = anim.NewAnimator(skeleton, append([]anim.Animation{anim.Animation{Name: "walk", Duration: keyframe04.SampleTime, Keyframes: []anim.Keyframe{keyframe00, keyframe01, keyframe02, keyframe03, keyframe04}},
+			anim.Animation{Name: "run", Duration: keyframe14.SampleTime, Keyframes: []anim.Keyframe{keyframe10, keyframe11, keyframe12, keyframe13, keyframe14}},
+			headTurn,
+			anim.Animation{Name: "idle", Duration: keyframe32.SampleTime, Keyframes: []anim.Keyframe{keyframe30, keyframe31, keyframe32}},
+			anim.Animation{Name: "jump", Duration: keyframe41.SampleTime, Keyframes: []anim.Keyframe{keyframe40, keyframe41}}}, importedAnimations...))
 		model.Animator.SetPlaybackRate(0, 1.3)
 		model.Animator.SetPlaybackRate(1, 1.3)
 		model.Animator.SetLooping(2, false)
+		model.Animator.SetLooping(4, false)
+		model.Animator.SetPlaybackRate(4, 2)
 		if err != nil {
 			panic(err)
 		}
-		playerShader, err := shader.NewProgram("player_diffuse_specular")
+		//Walk and run share their phase so the feet stay in step while the speed changes
+		ground, err := anim.NewBlendSpace1D("speed", []anim.BlendSample1D{
+			{Position: 0, Node: &anim.ClipNode{Animation: 3}},                            //Idle
+			{Position: 0.4, Node: &anim.ClipNode{Animation: 0, SyncGroup: "locomotion"}}, //Walk
+			{Position: 1, Node: &anim.ClipNode{Animation: 1, SyncGroup: "locomotion"}}})  //Run
+		if err != nil {
+			log.Fatalln(err)
+		}
+		//Leaving the ground starts the jump clip from its first frame, landing crossfades back
+		body := &anim.StateMachine{
+			States: []anim.State{{Name: "ground", Node: ground}, {Name: "jump", Node: &anim.ClipNode{Animation: 4}}},
+			Transitions: []anim.Transition{
+				{From: 0, To: 1, Conditions: []anim.Condition{{Parameter: "airborne", Comparison: anim.Greater, Value: 0.5}}, Duration: 0.1, Easing: anim.EaseOut},
+				{From: 1, To: 0, Conditions: []anim.Condition{{Parameter: "airborne", Comparison: anim.Less, Value: 0.5}}, Duration: 0.25, Easing: anim.EaseInOut, Interruptible: true},
+			}}
+		model.Animator.SetBlendTree(body)
+		if headMask != nil {
+			model.Animator.AddLayer(anim.Layer{Name: "head", Node: &anim.ClipNode{Animation: 2, Parameter: "head"}, Mode: anim.LayerAdditive, Mask: headMask, Weight: 1})
+		}
+		//The skinning shaders are specialized for the skeleton's bone count
+		model.Palette, err = types.NewBonePalette(len(skeleton.Bones))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		playerShader, err := shader.NewProgramFromFiles("player_diffuse_specular", "player_diffuse_specular", model.Palette.MaxBonesDefine())
+		if err != nil {
+			log.Fatalln(err)
+		}
+		playerDualQuaternionShader, err := shader.NewProgramFromFiles("player_dual_quaternion", "player_diffuse_specular", model.Palette.MaxBonesDefine())
 		if err != nil {
 			log.Fatalln(err)
 		}
@@ -99,7 +136,7 @@ func main() {
 		if err0 != nil {
 			log.Fatalln(err0)
 		}
-		shapeA := collision.GenerateCollisionPointsFromConvexMesh(colliderMesh)
+		shapeA := collision.ConvexHull(collision.GenerateCollisionPointsFromConvexMesh(colliderMesh))
 		dynamicMesh, _, err0, _ := collada.ParseMeshSkeleton("data/model/64verts.dae")
 		if err0 != nil {
 			log.Fatalln(err0)
@@ -127,6 +164,7 @@ func main() {
 			log.Fatalln(err)
 		}
 		level.Textures = []types.Texture{{squareTexture, "diffuse"}}
+		levelCollider := collision.NewTriangleMesh(collision.TrianglesFromMesh(level))
 		shaderDiffuseTexture, err := shader.NewProgram("diffuse_texture")
 		if err != nil {
 			log.Fatalln(err)
@@ -155,9 +193,11 @@ func main() {
 		frameTimer := frameTimer{gameLoopStart: float32(glfw.GetTime()), desiredFrameTime: 1 / float32(fps)}
 		editBone := int32(-1)
 		pressedN := false
+		rootMotion := false
+		pressedM := false
+		pressedQ := false
 		speed := float32(0)
 		head := float32(0.5)
-		height := float32(0)
 		red := mgl32.Vec4{1, 0, 0, 1}
 		purple := mgl32.Vec4{1, 0, 1, 1}
 		green := mgl32.Vec4{0, 1, 0, 1}
@@ -166,8 +206,11 @@ func main() {
 		//black := mgl32.Vec4{0, 0, 0, 1}
 		//cyan := mgl32.Vec4{0, 1, 1, 1}
 		environmentShader := shaderDiffuseTexture
-		shapeB := make([]mgl32.Vec3, len(shapeA))
-		CSO := make([]mgl32.Vec3, len(shapeA)*len(shapeB))
+		shapeB := collision.NewTransformed(shapeA, colliderPosition)
+		world := collision.NewWorld()
+		world.Add(shapeA)
+		shapeBHandle := world.Add(shapeB)
+		CSO := make([]mgl32.Vec3, len(shapeA)*len(shapeA))
 		collisionSteps := 15
 
 		for !window.ShouldClose() {
@@ -176,13 +219,15 @@ func main() {
 
 			//Get input
 			glfw.PollEvents()
-			handleInput(window, &worldGizmo, &frameTimer, &player, &camera, &colliderPosition, &lightPosition, &colliderRotation, &speed, &head, &height, &editBone, &collisionSteps, &pressedN, &environmentShader, shaderDiffuseTexture, shaderPointLitTexture, shaderDiffuseTextureWaving)
+			handleInput(window, &worldGizmo, &frameTimer, &player, &camera, &colliderPosition, &lightPosition, &colliderRotation, &speed, &head, &editBone, &collisionSteps, &pressedN, &rootMotion, &pressedM, &model.Skinning, &pressedQ, int32(model.Palette.BoneCount), &environmentShader, shaderDiffuseTexture, shaderPointLitTexture, shaderDiffuseTextureWaving)
 
 			//update variables
 			colliderMat = mgl32.HomogRotate3DY(colliderRotation)
 			colliderMat = mgl32.Translate3D(colliderPosition[0], colliderPosition[1], colliderPosition[2]).Mul4(colliderMat)
-			transformShape(shapeA, shapeB, colliderPosition, colliderRotation)
-			if err := calcMinkowskiDiff(shapeA, shapeB, CSO); err != nil {
+			shapeB.Position = colliderPosition
+			shapeB.Rotation = mgl32.QuatRotate(colliderRotation, worldGizmo.yAxis)
+			world.Update(shapeBHandle)
+			if err := calcMinkowskiDiff(shapeA, shapeA, colliderMat, CSO); err != nil {
 				panic(err)
 			}
 
@@ -190,18 +235,21 @@ func main() {
 			camera.Update(x, y, speed, &player.Dir)
 			modelRotationMatrix := toComMatrix.Mul4(mgl32.HomogRotate3D(player.TiltAngle, player.TiltAxis).Mul4(mgl32.HomogRotate3DY(player.Angle).Mul4(toComInvMatrix)))
 			modelMatrix := mgl32.Translate3D(player.Position[0], player.Position[1], player.Position[2]).Mul4(modelRotationMatrix)
-			model.Animator.Update(frameTimer.deltaTime, func() {
-				a := model.Animator
-				a.SampleAtGlobalTime(0, 0)     //Sample Walk
-				a.SampleAtGlobalTime(1, 1)     //Sample Run
-				a.LinearBlend(0, 1, speed, 1)  //LERP(Walk, Run) => move
-				a.SampleAtGlobalTime(3, 0)     //Sample Idle
-				a.LinearBlend(0, 1, speed, 0)  //LERP(move, Idle) => ground
-				a.SampleLinear(4, height, 1)   //Sample Jump
-				a.LinearBlend(0, 1, height, 0) //LERP(ground, Jump) => body
-				a.SampleLinear(2, head, 1)     //Sample head Turn
-				a.AdditiveBlend(0, 1, 1.0, 0)  //AdditiveBlend(body, head) => final
-			})
+			model.Animator.SetParameter("speed", speed)
+			model.Animator.SetParameter("head", head)
+			if player.InAir {
+				model.Animator.SetParameter("airborne", 1)
+			} else {
+				model.Animator.SetParameter("airborne", 0)
+			}
+			model.Animator.SetRootMotion(rootMotion)
+			model.Animator.Update(frameTimer.deltaTime)
+			if rootMotion {
+				//The clips move the player, input only steers and picks the speed
+				motion := model.Animator.RootMotion()
+				player.Position = player.Position.Add(mgl32.Rotate3DY(player.Angle).Mul3x1(motion.Translation))
+			}
+			collidePlayer(&player, levelCollider)
 
 			//FPS display, and debug information
 			if frameTimer.isSecondMark {
@@ -221,14 +269,23 @@ func main() {
 			level.Draw(environmentShader, gl.TRIANGLES)
 
 			//Update the player shader
-			gl.UseProgram(playerShader)
-			gl.UniformMatrix4fv(gl.GetUniformLocation(playerShader, gl.Str("vp_mat\x00")), 1, false, &camera.VPMatrix[0])
-			gl.UniformMatrix4fv(gl.GetUniformLocation(playerShader, gl.Str("model_mat\x00")), 1, false, &modelMatrix[0])
-			gl.UniformMatrix4fv(gl.GetUniformLocation(playerShader, gl.Str("model_rotation_mat\x00")), 1, false, &modelRotationMatrix[0])
-			gl.Uniform3f(gl.GetUniformLocation(playerShader, gl.Str("light_position\x00")), lightPosition[0], lightPosition[1], lightPosition[2])
-			gl.UniformMatrix4fv(gl.GetUniformLocation(playerShader, gl.Str("bone_mat\x00")), 15, false, &model.Animator.GlobalPoseMatrices[0][0])
-			gl.Uniform1i(gl.GetUniformLocation(playerShader, gl.Str("edit_bone\x00")), editBone)
-			model.Mesh.Draw(playerShader, gl.TRIANGLES)
+			skinningShader := playerShader
+			if model.Skinning == types.SKIN_DUAL_QUATERNION {
+				skinningShader = playerDualQuaternionShader
+			}
+			gl.UseProgram(skinningShader)
+			gl.UniformMatrix4fv(gl.GetUniformLocation(skinningShader, gl.Str("vp_mat\x00")), 1, false, &camera.VPMatrix[0])
+			gl.UniformMatrix4fv(gl.GetUniformLocation(skinningShader, gl.Str("model_mat\x00")), 1, false, &modelMatrix[0])
+			gl.UniformMatrix4fv(gl.GetUniformLocation(skinningShader, gl.Str("model_rotation_mat\x00")), 1, false, &modelRotationMatrix[0])
+			gl.Uniform3f(gl.GetUniformLocation(skinningShader, gl.Str("light_position\x00")), lightPosition[0], lightPosition[1], lightPosition[2])
+			if model.Skinning == types.SKIN_DUAL_QUATERNION {
+				model.Palette.UploadDualQuaternions(model.Animator.GlobalDualQuaternions)
+			} else {
+				model.Palette.UploadMatrices(model.Animator.GlobalPoseMatrices)
+			}
+			model.Palette.Bind(skinningShader)
+			gl.Uniform1i(gl.GetUniformLocation(skinningShader, gl.Str("edit_bone\x00")), editBone)
+			model.Mesh.Draw(skinningShader, gl.TRIANGLES)
 
 			//DRAW POINT MESHES
 			gl.PointSize(8)
@@ -245,11 +302,12 @@ func main() {
 			gl.Uniform1i(gl.GetUniformLocation(debugShader, gl.Str("var_count\x00")), int32(len(shapeA)))
 			gl.Uniform4fv(gl.GetUniformLocation(debugShader, gl.Str("var_color\x00")), 1, &green[0])
 			dynamicMesh.Draw(debugShader, gl.POINTS)
-			//Draw shape b
-			gl.Uniform3fv(gl.GetUniformLocation(debugShader, gl.Str("var_positions\x00")), int32(len(shapeB)), &shapeB[0][0])
-			gl.Uniform1i(gl.GetUniformLocation(debugShader, gl.Str("var_count\x00")), int32(len(shapeB)))
+			//Draw shape b, the points of shape a moved by the collider matrix
+			colliderVPMat := camera.VPMatrix.Mul4(colliderMat)
+			gl.UniformMatrix4fv(gl.GetUniformLocation(debugShader, gl.Str("vp_mat\x00")), 1, false, &colliderVPMat[0])
 			gl.Uniform4fv(gl.GetUniformLocation(debugShader, gl.Str("var_color\x00")), 1, &blue[0])
 			dynamicMesh.Draw(debugShader, gl.POINTS)
+			gl.UniformMatrix4fv(gl.GetUniformLocation(debugShader, gl.Str("vp_mat\x00")), 1, false, &camera.VPMatrix[0])
 			//Draw the minokwski difference
 			//gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
 			gl.Uniform3fv(gl.GetUniformLocation(debugShader, gl.Str("var_positions\x00")), int32(len(CSO)), &CSO[0][0])
@@ -269,8 +327,16 @@ func main() {
 			dynamicMesh.Draw(simplexShader, gl.LINE_STRIP)
 
 			color := green
+			contacts := world.Collide()
 			if collided {
 				color = blue
+				if len(contacts) > 0 && frameTimer.isSecondMark {
+					fmt.Printf("penetration depth %v;\t normal %v\n", contacts[0].Depth, contacts[0].Normal)
+				}
+			} else if frameTimer.isSecondMark {
+				if proximity, ok := collision.Distance(shapeA, shapeB, 1e-4); ok {
+					fmt.Printf("distance %v;\t closest points %v %v\n", proximity.Distance, proximity.PointA, proximity.PointB)
+				}
 			}
 
 			//Green collider
@@ -292,23 +358,67 @@ func main() {
 	}(window)
 }
 
-func calcMinkowskiDiff(shapeA, shapeB, CSO []mgl32.Vec3) error {
+func calcMinkowskiDiff(shapeA, shapeB []mgl32.Vec3, transformB mgl32.Mat4, CSO []mgl32.Vec3) error {
 	if len(CSO) != len(shapeA)*len(shapeB) {
 		return fmt.Errorf("Mikowski: number of points in cso does not match input shapes.")
 	}
 	vertInd := 0
 	for i := 0; i < len(shapeA); i++ {
 		for j := 0; j < len(shapeB); j++ {
-			CSO[vertInd] = shapeA[i].Sub(shapeB[j])
+			CSO[vertInd] = shapeA[i].Sub(mgl32.TransformCoordinate(shapeB[j], transformB))
 			vertInd++
 		}
 	}
 	return nil
 }
 
-func transformShape(input, output []mgl32.Vec3, translation mgl32.Vec3, rotationAngle float32) {
-	for i := 0; i < len(input); i++ {
-		output[i] = (mgl32.Rotate3DY(rotationAngle)).Mul3x1(input[i]).Add(translation)
+// collidePlayer pushes the player's capsule out of the level, along the
+// faces of the triangles first. Upward contacts put the player on the ground,
+// a player that walks off it starts falling. The y = 0 plane in handleInput
+// still catches the player where the level has no floor
+func collidePlayer(player *player, level *collision.TriangleMesh) {
+	const radius, height, groundProbe = float32(0.3), float32(1.6), float32(0.05)
+	capsule := func(offset float32) collision.Capsule {
+		feet := player.Position.Add(mgl32.Vec3{0, offset, 0})
+		return collision.Capsule{A: feet.Add(mgl32.Vec3{0, radius, 0}), B: feet.Add(mgl32.Vec3{0, height - radius, 0}), Radius: radius}
+	}
+	isGround := func(contact collision.TriangleContact) bool {
+		return contact.Normal[1] > 0.7
+	}
+	grounded := false
+	for i := 0; i < 4; i++ {
+		contacts := level.Collide(capsule(0))
+		if len(contacts) == 0 {
+			break
+		}
+		deepest := contacts[0]
+		for _, contact := range contacts[1:] {
+			if contact.Face && !deepest.Face || contact.Face == deepest.Face && contact.Depth > deepest.Depth {
+				deepest = contact
+			}
+		}
+		player.Position = player.Position.Add(deepest.Normal.Mul(deepest.Depth))
+		if isGround(deepest) {
+			grounded = true
+			if player.Velocity[1] < 0 {
+				player.Velocity[1] = 0
+			}
+		} else if into := player.Velocity.Dot(deepest.Normal); into < 0 {
+			//Slide along walls
+			player.Velocity = player.Velocity.Sub(deepest.Normal.Mul(into))
+		}
+	}
+	if grounded && player.InAir && player.Velocity[1] <= 0 {
+		player.InAir = false
+	}
+	if !player.InAir && !grounded && player.Position[1] > 0 {
+		//Standing players rest on the floor without sinking into it
+		for _, contact := range level.Collide(capsule(-groundProbe)) {
+			if isGround(contact) {
+				return
+			}
+		}
+		player.InAir = true
 	}
 }
 
@@ -330,7 +440,7 @@ func clampInt(a, i, b int) int {
 }
 
 //Input function
-func handleInput(window *glfw.Window, world *gizmo, frameTimer *frameTimer, player *player, camera *camera, colliderPosition, lightPosition *mgl32.Vec3, colliderRotation, speed, head, height *float32, editBone *int32, collisionSteps *int, pressedN *bool, envShader *uint32, firstShader, secondShader, thirdShader uint32) {
+func handleInput(window *glfw.Window, world *gizmo, frameTimer *frameTimer, player *player, camera *camera, colliderPosition, lightPosition *mgl32.Vec3, colliderRotation, speed, head *float32, editBone *int32, collisionSteps *int, pressedN, rootMotion, pressedM *bool, skinning *int, pressedQ *bool, boneCount int32, envShader *uint32, firstShader, secondShader, thirdShader uint32) {
 	var maxTiltAngle float32 = 0.25
 	var lightSpeed float32 = 3
 	var maxSpeed float32 = 10
@@ -416,8 +526,12 @@ func handleInput(window *glfw.Window, world *gizmo, frameTimer *frameTimer, play
 		player.TiltAxis = player.TiltAxis.Normalize()
 	}
 
-	//Update the player's position
-	player.Position = player.Position.Add(player.Velocity.Mul(deltaTime))
+	//Update the player's position, horizontally only when not driven by root motion
+	if *rootMotion {
+		player.Position[1] += player.Velocity[1] * deltaTime
+	} else {
+		player.Position = player.Position.Add(player.Velocity.Mul(deltaTime))
+	}
 
 	//Determine the player's rotation around the y axis
 	dtr := float32(math.Pi / 180)
@@ -488,11 +602,31 @@ func handleInput(window *glfw.Window, world *gizmo, frameTimer *frameTimer, play
 			*collisionSteps -= 2
 		}
 		if *editBone >= 0 {
-			*editBone %= 15
+			*editBone %= boneCount
 		}
 		*collisionSteps = clampInt(0, *collisionSteps, 20)
 		*pressedN = false
 	}
+	//ROOT MOTION TOGGLE
+	if window.GetKey(glfw.KeyM) == glfw.Press {
+		*pressedM = true
+	}
+	if *pressedM && window.GetKey(glfw.KeyM) == glfw.Release {
+		*rootMotion = !*rootMotion
+		*pressedM = false
+	}
+	//SKINNING TOGGLE
+	if window.GetKey(glfw.KeyQ) == glfw.Press {
+		*pressedQ = true
+	}
+	if *pressedQ && window.GetKey(glfw.KeyQ) == glfw.Release {
+		if *skinning == types.SKIN_LINEAR_BLEND {
+			*skinning = types.SKIN_DUAL_QUATERNION
+		} else {
+			*skinning = types.SKIN_LINEAR_BLEND
+		}
+		*pressedQ = false
+	}
 	//ANIMATION BLENDING
 	if window.GetKey(glfw.KeyL) == glfw.Press {
 		player.LookAtLight = true
@@ -515,14 +649,6 @@ func handleInput(window *glfw.Window, world *gizmo, frameTimer *frameTimer, play
 	*speed = float32(math.Sqrt(float64((player.Velocity[0]*player.Velocity[0])+(player.Velocity[2]*player.Velocity[2])))) / maxSpeed
 	*head = clamp(-1, *head, 1)
 	*speed = clamp(0, *speed, 1)
-	//jump blend
-	maxFallTime := initialJumpSpeed / 9.81
-	maxHeight := maxFallTime * maxFallTime * 9.81 / 2
-	if player.Velocity[1] > 0 {
-		*height = clamp(0.2, 2*player.Position[1]/maxHeight, 1)
-	} else {
-		*height = player.Position[1] / maxHeight
-	}
 	//RESET BUTTON
 	if window.GetKey(glfw.KeyR) == glfw.Press {
 		*lightPosition = mgl32.Vec3{}
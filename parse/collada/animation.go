@@ -0,0 +1,397 @@
+package collada
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"training/engine/anim"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// boneChannel holds the keys of one animated joint, already converted into the
+// bone space transforms anim.Animator expects
+type boneChannel struct {
+	boneIndex     int
+	times         []float32
+	transforms    []anim.Transform
+	interpolation anim.Interpolation
+	tangents      *anim.Tangents
+}
+
+// matrixSampler is a sampler whose output and tangents are float4x4 matrices,
+// Bezier tangents additionally carry the time of their control point
+type matrixSampler struct {
+	times         []float32
+	matrices      []mgl32.Mat4
+	interpolation anim.Interpolation
+	inTangents    []mgl32.Mat4
+	outTangents   []mgl32.Mat4
+	inTimes       []float32
+	outTimes      []float32
+}
+
+func extractAnimations(collada *collada, skeleton *anim.Skeleton) ([]anim.Animation, error) {
+	if collada.LibraryAnimations == nil {
+		return nil, nil
+	}
+	armature := findArmature(collada.LibraryVisualScenes)
+	if armature == nil {
+		return nil, fmt.Errorf("collada: animations: no armature found")
+	}
+	armatureMatrix, err := nodeMatrix(armature)
+	if err != nil {
+		return nil, fmt.Errorf("collada: animations: armature matrix: %v", err)
+	}
+	nodeIdToBone := make(map[string]int)
+	nodeIdToSid := make(map[string]string)
+	mapJointNodes(armature.Nodes, skeleton, nodeIdToBone, nodeIdToSid)
+
+	//Channels of every <animation> element, including the ones of its children
+	channelsById := make(map[string][]boneChannel)
+	var allChannels []boneChannel
+	for i := range collada.LibraryAnimations.Animations {
+		channels, err := extractAnimationChannels(&collada.LibraryAnimations.Animations[i], skeleton, armatureMatrix, nodeIdToBone, nodeIdToSid, channelsById)
+		if err != nil {
+			return nil, err
+		}
+		allChannels = append(allChannels, channels...)
+	}
+	if len(allChannels) == 0 {
+		//Only object or camera animations, there is no clip to build
+		return nil, nil
+	}
+
+	if collada.LibraryClips == nil || len(collada.LibraryClips.Clips) == 0 {
+		start, end := channelTimeRange(allChannels)
+		return []anim.Animation{buildAnimation("default", allChannels, start, end)}, nil
+	}
+	animations := make([]anim.Animation, 0, len(collada.LibraryClips.Clips))
+	for _, clip := range collada.LibraryClips.Clips {
+		var channels []boneChannel
+		for _, instance := range clip.InstanceAnimations {
+			instanceChannels, present := channelsById[strings.TrimPrefix(instance.Url, "#")]
+			if !present {
+				return nil, fmt.Errorf("collada: clip %v: unknown animation %v", clip.Id, instance.Url)
+			}
+			channels = append(channels, instanceChannels...)
+		}
+		start, end := channelTimeRange(channels)
+		if clip.Start != "" {
+			if start, err = parseFloat(clip.Start); err != nil {
+				return nil, fmt.Errorf("collada: clip %v start: %v", clip.Id, err)
+			}
+		}
+		if clip.End != "" {
+			if end, err = parseFloat(clip.End); err != nil {
+				return nil, fmt.Errorf("collada: clip %v end: %v", clip.Id, err)
+			}
+		}
+		name := clip.Name
+		if name == "" {
+			name = clip.Id
+		}
+		animations = append(animations, buildAnimation(name, channels, start, end))
+	}
+	return animations, nil
+}
+
+func extractAnimationChannels(animation *animation, skeleton *anim.Skeleton, armatureMatrix mgl32.Mat4, nodeIdToBone map[string]int, nodeIdToSid map[string]string, channelsById map[string][]boneChannel) ([]boneChannel, error) {
+	var channels []boneChannel
+	for _, ch := range animation.Channels {
+		split := strings.Split(ch.Target, "/")
+		if len(split) != 2 {
+			return nil, fmt.Errorf("collada: animation %v: unsupported channel target %v", animation.Id, ch.Target)
+		}
+		boneIndex, isBone := nodeIdToBone[split[0]]
+		if !isBone {
+			//Object animations are not part of the skeleton
+			continue
+		}
+		if split[1] != nodeIdToSid[split[0]] {
+			return nil, fmt.Errorf("collada: animation %v: only matrix targets are supported, got %v", animation.Id, ch.Target)
+		}
+		samp, err := readMatrixSampler(animation, strings.TrimPrefix(ch.Source, "#"))
+		if err != nil {
+			return nil, fmt.Errorf("collada: animation %v: %v", animation.Id, err)
+		}
+		parentBindPose := armatureMatrix
+		if bone := &skeleton.Bones[boneIndex]; boneIndex != skeleton.RootIndex {
+			parentBindPose = skeleton.Bones[bone.ParentIndex].BindPose
+		}
+		//The animator poses a bone with BindPose*T*InverseBindPose relative to its parent
+		toBone := skeleton.Bones[boneIndex].InverseBindPose.Mul4(parentBindPose)
+		toTransform := func(local mgl32.Mat4) anim.Transform {
+			return anim.Mat4ToTransform(toBone.Mul4(local))
+		}
+		channel := boneChannel{boneIndex: boneIndex, times: samp.times, transforms: make([]anim.Transform, len(samp.matrices)), interpolation: samp.interpolation}
+		for k, local := range samp.matrices {
+			channel.transforms[k] = toTransform(local)
+		}
+		if samp.inTangents != nil {
+			channel.tangents = &anim.Tangents{In: make([]anim.Transform, len(samp.times)), Out: make([]anim.Transform, len(samp.times)), InTimes: samp.inTimes, OutTimes: samp.outTimes}
+			for k, local := range samp.matrices {
+				if samp.interpolation == anim.InterpolationBezier {
+					channel.tangents.In[k] = toTransform(samp.inTangents[k])
+					channel.tangents.Out[k] = toTransform(samp.outTangents[k])
+				} else {
+					channel.tangents.In[k] = transformSlope(toTransform, local, samp.inTangents[k])
+					channel.tangents.Out[k] = transformSlope(toTransform, local, samp.outTangents[k])
+				}
+			}
+		}
+		channels = append(channels, channel)
+	}
+	for i := range animation.Animations {
+		children, err := extractAnimationChannels(&animation.Animations[i], skeleton, armatureMatrix, nodeIdToBone, nodeIdToSid, channelsById)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, children...)
+	}
+	if animation.Id != "" {
+		channelsById[animation.Id] = channels
+	}
+	return channels, nil
+}
+
+func readMatrixSampler(animation *animation, samplerId string) (*matrixSampler, error) {
+	var samp *sampler
+	for i := range animation.Samplers {
+		if animation.Samplers[i].Id == samplerId {
+			samp = &animation.Samplers[i]
+		}
+	}
+	if samp == nil {
+		return nil, fmt.Errorf("sampler %v not found", samplerId)
+	}
+	result := &matrixSampler{}
+	var outputs, inTangents, outTangents []float32
+	var inStride, outStride int
+	for _, in := range samp.Inputs {
+		src := findSource(animation.Sources, strings.TrimPrefix(in.Source, "#"))
+		if in.Semantic == "INTERPOLATION" {
+			if src == nil || src.NameArray == nil {
+				return nil, fmt.Errorf("sampler %v: missing name source %v", samplerId, in.Source)
+			}
+			modes := strings.Fields(src.NameArray.Content)
+			if len(modes) == 0 {
+				continue
+			}
+			//A channel has a single mode, the one of its first key
+			var err error
+			if result.interpolation, err = parseInterpolation(modes[0]); err != nil {
+				return nil, fmt.Errorf("sampler %v: %v", samplerId, err)
+			}
+			continue
+		}
+		if src == nil || src.FloatArray == nil {
+			if in.Semantic == "INPUT" || in.Semantic == "OUTPUT" {
+				return nil, fmt.Errorf("sampler %v: missing float source %v", samplerId, in.Source)
+			}
+			continue
+		}
+		stride, _ := strconv.Atoi(src.TechniqueCommon.Accessor.Stride)
+		var err error
+		switch in.Semantic {
+		case "INPUT":
+			result.times, err = stringToFloatArray(src.FloatArray.Content)
+		case "OUTPUT":
+			if stride != 16 {
+				return nil, fmt.Errorf("sampler %v: expected float4x4 output, got stride %v", samplerId, stride)
+			}
+			outputs, err = stringToFloatArray(src.FloatArray.Content)
+		case "IN_TANGENT":
+			inStride = stride
+			inTangents, err = stringToFloatArray(src.FloatArray.Content)
+		case "OUT_TANGENT":
+			outStride = stride
+			outTangents, err = stringToFloatArray(src.FloatArray.Content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sampler %v: %v", samplerId, err)
+		}
+	}
+	if len(result.times) == 0 || len(outputs) != 16*len(result.times) {
+		return nil, fmt.Errorf("sampler %v: %v key times for %v output floats", samplerId, len(result.times), len(outputs))
+	}
+	result.matrices = readMatrices(outputs, len(result.times), 16)
+	if result.interpolation != anim.InterpolationHermite && result.interpolation != anim.InterpolationBezier {
+		return result, nil
+	}
+	if inTangents == nil || outTangents == nil {
+		//Without tangents the curve falls back to Catmull-Rom
+		return result, nil
+	}
+	if inStride != outStride || (inStride != 16 && inStride != 32) {
+		return nil, fmt.Errorf("sampler %v: expected float4x4 tangents, got strides %v and %v", samplerId, inStride, outStride)
+	}
+	if len(inTangents) != inStride*len(result.times) || len(outTangents) != outStride*len(result.times) {
+		return nil, fmt.Errorf("sampler %v: %v key times for %v and %v tangent floats", samplerId, len(result.times), len(inTangents), len(outTangents))
+	}
+	result.inTangents = readMatrices(inTangents, len(result.times), inStride)
+	result.outTangents = readMatrices(outTangents, len(result.times), outStride)
+	if result.interpolation == anim.InterpolationBezier {
+		if inStride != 32 {
+			return nil, fmt.Errorf("sampler %v: Bezier tangents need (time, value) control points", samplerId)
+		}
+		result.inTimes = readControlTimes(inTangents, len(result.times))
+		result.outTimes = readControlTimes(outTangents, len(result.times))
+	}
+	return result, nil
+}
+
+func parseInterpolation(name string) (anim.Interpolation, error) {
+	switch name {
+	case "LINEAR":
+		return anim.InterpolationLinear, nil
+	case "STEP":
+		return anim.InterpolationStep, nil
+	case "HERMITE":
+		return anim.InterpolationHermite, nil
+	case "BEZIER":
+		return anim.InterpolationBezier, nil
+	}
+	return anim.InterpolationLinear, fmt.Errorf("unsupported interpolation %v", name)
+}
+
+// readMatrices reads count matrices, a stride of 32 holds a (time, value) pair
+// per matrix element of which only the value is kept
+func readMatrices(floats []float32, count, stride int) []mgl32.Mat4 {
+	matrices := make([]mgl32.Mat4, count)
+	for i := range matrices {
+		for j := 0; j < 16; j++ {
+			if stride == 32 {
+				matrices[i][j] = floats[32*i+2*j+1]
+			} else {
+				matrices[i][j] = floats[16*i+j]
+			}
+		}
+		matrices[i] = matrices[i].Transpose()
+	}
+	return matrices
+}
+
+// readControlTimes averages the times of the element control points, they
+// only differ when the matrix elements were keyed separately
+func readControlTimes(floats []float32, count int) []float32 {
+	times := make([]float32, count)
+	for i := range times {
+		for j := 0; j < 16; j++ {
+			times[i] += floats[32*i+2*j] / 16
+		}
+	}
+	return times
+}
+
+// transformSlope turns a matrix derivative into a per second Transform slope
+// by stepping a small distance along it
+func transformSlope(toTransform func(mgl32.Mat4) anim.Transform, local, slope mgl32.Mat4) anim.Transform {
+	const h = 1e-3
+	a, b := toTransform(local), toTransform(local.Add(slope.Mul(h)))
+	if a.Rotation.Dot(b.Rotation) < 0 {
+		b.Rotation = b.Rotation.Scale(-1)
+	}
+	var result anim.Transform
+	for i := 0; i < 3; i++ {
+		result.Translate[i] = (b.Translate[i] - a.Translate[i]) / h
+		result.Scale[i] = (b.Scale[i] - a.Scale[i]) / h
+	}
+	result.Rotation = b.Rotation.Sub(a.Rotation).Scale(1 / h)
+	return result
+}
+
+func buildAnimation(name string, channels []boneChannel, start, end float32) anim.Animation {
+	animation := anim.Animation{Name: name, Duration: end - start, Tracks: make([]anim.Track, len(channels))}
+	for i, channel := range channels {
+		times := make([]float32, len(channel.times))
+		for k, t := range channel.times {
+			times[k] = t - start
+		}
+		tangents := channel.tangents
+		if tangents != nil && tangents.InTimes != nil {
+			shifted := *tangents
+			shifted.InTimes, shifted.OutTimes = make([]float32, len(times)), make([]float32, len(times))
+			for k := range times {
+				shifted.InTimes[k] = tangents.InTimes[k] - start
+				shifted.OutTimes[k] = tangents.OutTimes[k] - start
+			}
+			tangents = &shifted
+		}
+		animation.Tracks[i] = anim.NewCurveTrack(channel.boneIndex, times, channel.transforms, channel.interpolation, tangents)
+	}
+	return animation
+}
+
+func channelTimeRange(channels []boneChannel) (float32, float32) {
+	if len(channels) == 0 {
+		return 0, 0
+	}
+	start, end := channels[0].times[0], channels[0].times[0]
+	for _, channel := range channels {
+		if first := channel.times[0]; first < start {
+			start = first
+		}
+		if last := channel.times[len(channel.times)-1]; last > end {
+			end = last
+		}
+	}
+	return start, end
+}
+
+func mapJointNodes(nodes []node, skeleton *anim.Skeleton, nodeIdToBone map[string]int, nodeIdToSid map[string]string) {
+	for i := range nodes {
+		if nodes[i].Type != "JOINT" {
+			continue
+		}
+		for b := range skeleton.Bones {
+			if skeleton.Bones[b].Name == nodes[i].Name {
+				nodeIdToBone[nodes[i].Id] = b
+				nodeIdToSid[nodes[i].Id] = nodes[i].Matrix.Sid
+			}
+		}
+		mapJointNodes(nodes[i].Nodes, skeleton, nodeIdToBone, nodeIdToSid)
+	}
+}
+
+func findArmature(libraryVisualScenes *libraryVisualScenes) *node {
+	if libraryVisualScenes == nil {
+		return nil
+	}
+	for i := range libraryVisualScenes.VisualScene.Nodes {
+		if libraryVisualScenes.VisualScene.Nodes[i].Name == "Armature" {
+			return &libraryVisualScenes.VisualScene.Nodes[i]
+		}
+	}
+	return nil
+}
+
+func findSource(sources []source, id string) *source {
+	for i := range sources {
+		if sources[i].Id == id {
+			return &sources[i]
+		}
+	}
+	return nil
+}
+
+func nodeMatrix(n *node) (mgl32.Mat4, error) {
+	if strings.TrimSpace(n.Matrix.Content) == "" {
+		return mgl32.Ident4(), nil
+	}
+	floats, err := stringToFloatArray(n.Matrix.Content)
+	if err != nil {
+		return mgl32.Mat4{}, err
+	}
+	if len(floats) != 16 {
+		return mgl32.Mat4{}, fmt.Errorf("expected 16 floats in matrix of node %v, got %v", n.Id, len(floats))
+	}
+	var m mgl32.Mat4
+	copy(m[:], floats)
+	return m.Transpose(), nil
+}
+
+func parseFloat(str string) (float32, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(str), 32)
+	return float32(f), err
+}
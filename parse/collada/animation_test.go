@@ -0,0 +1,422 @@
+package collada
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"training/engine/anim"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// armatureDocument is a two bone rig, the child Bone_001 listed first in the
+// skin so bone indices differ from the node order, node ids differ from the
+// joint names like Blender exports them
+func armatureDocument(animations, clips string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2005/11/COLLADASchema" version="1.4.1">
+  <library_controllers>
+    <controller id="Armature_Cube-skin" name="Armature">
+      <skin source="#Cube-mesh">
+        <bind_shape_matrix>%v</bind_shape_matrix>
+        <source id="Armature_Cube-skin-joints">
+          <Name_array id="Armature_Cube-skin-joints-array" count="2">Bone_001 Bone</Name_array>
+          <technique_common><accessor source="#Armature_Cube-skin-joints-array" count="2" stride="1"/></technique_common>
+        </source>
+        <source id="Armature_Cube-skin-bind_poses">
+          <float_array id="Armature_Cube-skin-bind_poses-array" count="32">%v</float_array>
+          <technique_common><accessor source="#Armature_Cube-skin-bind_poses-array" count="2" stride="16"/></technique_common>
+        </source>
+        <source id="Armature_Cube-skin-weights">
+          <float_array id="Armature_Cube-skin-weights-array" count="1">1</float_array>
+          <technique_common><accessor source="#Armature_Cube-skin-weights-array" count="1" stride="1"/></technique_common>
+        </source>
+      </skin>
+    </controller>
+  </library_controllers>
+  <library_visual_scenes>
+    <visual_scene id="Scene" name="Scene">
+      <node id="Armature" name="Armature" type="NODE">
+        <matrix sid="transform">%v</matrix>
+        <node id="Armature_Bone" name="Bone" sid="Bone" type="JOINT">
+          <matrix sid="transform">%v</matrix>
+          <node id="Armature_Bone_001" name="Bone_001" sid="Bone_001" type="JOINT">
+            <matrix sid="transform">%v</matrix>
+          </node>
+        </node>
+      </node>
+      <node id="Cube" name="Cube" type="NODE">
+        <matrix sid="transform">%v</matrix>
+      </node>
+    </visual_scene>
+  </library_visual_scenes>
+  <library_animations>%v</library_animations>%v
+</COLLADA>`,
+		rowMajor(mgl32.Ident4()),
+		rowMajor(testBindPose(1).Inv(), testBindPose(0).Inv()),
+		rowMajor(testArmatureMatrix),
+		rowMajor(mgl32.Translate3D(0, 0, 1)),
+		rowMajor(mgl32.Translate3D(0, 1, 0)),
+		rowMajor(mgl32.Ident4()),
+		animations, clips)
+}
+
+var testArmatureMatrix = mgl32.Translate3D(0.5, 0, 1).Mul4(mgl32.HomogRotate3DX(-math.Pi / 2))
+
+// testBindPose is the bind pose of bone 0 (Bone_001) and 1 (Bone)
+func testBindPose(boneIndex int) mgl32.Mat4 {
+	if boneIndex == 0 {
+		return mgl32.Translate3D(0, 1, 1)
+	}
+	return mgl32.Translate3D(0, 0, 1)
+}
+
+// testSampler writes a sampler with its sources as an <animation>, tangents are
+// left out when nil
+type testSampler struct {
+	mode                    string
+	times, outputs          []float32
+	inTangents, outTangents []float32
+	inStride, outStride     int
+}
+
+func (s testSampler) animation(id, target string) string {
+	source := func(suffix string, floats []float32, stride int) string {
+		return fmt.Sprintf(`
+      <source id="%v-%v">
+        <float_array id="%v-%v-array" count="%v">%v</float_array>
+        <technique_common><accessor source="#%v-%v-array" count="%v" stride="%v"/></technique_common>
+      </source>`, id, suffix, id, suffix, len(floats), floatString(floats), id, suffix, len(floats)/stride, stride)
+	}
+	sources := source("input", s.times, 1) + source("output", s.outputs, 16)
+	sources += fmt.Sprintf(`
+      <source id="%v-interpolation">
+        <Name_array id="%v-interpolation-array" count="%v">%v</Name_array>
+      </source>`, id, id, len(s.times), strings.TrimSpace(strings.Repeat(s.mode+" ", len(s.times))))
+	inputs := fmt.Sprintf(`
+        <input semantic="INPUT" source="#%v-input"/>
+        <input semantic="OUTPUT" source="#%v-output"/>
+        <input semantic="INTERPOLATION" source="#%v-interpolation"/>`, id, id, id)
+	if s.inTangents != nil {
+		sources += source("intangent", s.inTangents, s.inStride) + source("outtangent", s.outTangents, s.outStride)
+		inputs += fmt.Sprintf(`
+        <input semantic="IN_TANGENT" source="#%v-intangent"/>
+        <input semantic="OUT_TANGENT" source="#%v-outtangent"/>`, id, id)
+	}
+	return fmt.Sprintf(`
+    <animation id="%v">%v
+      <sampler id="%v-sampler">%v
+      </sampler>
+      <channel source="#%v-sampler" target="%v"/>
+    </animation>`, id, sources, id, inputs, id, target)
+}
+
+// rowMajor writes matrices the way collada stores them
+func rowMajor(matrices ...mgl32.Mat4) string {
+	return floatString(matrixFloats(matrices...))
+}
+
+func matrixFloats(matrices ...mgl32.Mat4) []float32 {
+	var floats []float32
+	for _, m := range matrices {
+		transposed := m.Transpose()
+		floats = append(floats, transposed[:]...)
+	}
+	return floats
+}
+
+func floatString(floats []float32) string {
+	strs := make([]string, len(floats))
+	for i, f := range floats {
+		strs[i] = strconv.FormatFloat(float64(f), 'g', -1, 32)
+	}
+	return strings.Join(strs, " ")
+}
+
+func parseDocument(t *testing.T, document string) (*collada, *anim.Skeleton) {
+	t.Helper()
+	var coll collada
+	if err := xml.Unmarshal([]byte(document), &coll); err != nil {
+		t.Fatalf("collada test: %v", err)
+	}
+	skeleton, err := extractSkeleton(&coll.LibraryControllers.Controllers[0].Skin, coll.LibraryVisualScenes)
+	if err != nil {
+		t.Fatalf("collada test: %v", err)
+	}
+	return &coll, skeleton
+}
+
+// sampleWorld plays the first animation up to time, clamped so its last key
+// can be sampled, and returns the armature space matrix of every joint
+func sampleWorld(t *testing.T, skeleton *anim.Skeleton, animations []anim.Animation, time float32) []mgl32.Mat4 {
+	t.Helper()
+	animator, err := anim.NewAnimator(skeleton, animations)
+	if err != nil {
+		t.Fatalf("collada test: %v", err)
+	}
+	animator.SetBlendTree(&anim.ClipNode{Animation: 0})
+	animator.SetLooping(0, false)
+	animator.Update(time)
+	world := make([]mgl32.Mat4, len(skeleton.Bones))
+	for i := range world {
+		world[i] = animator.GlobalPoseMatrices[i].Mul4(skeleton.Bones[i].BindPose)
+	}
+	return world
+}
+
+func matrixNear(a, b mgl32.Mat4) bool {
+	for i := range a {
+		if mgl32.Abs(a[i]-b[i]) > 1e-4 {
+			return false
+		}
+	}
+	return true
+}
+
+func rootKeys() []mgl32.Mat4 {
+	return []mgl32.Mat4{
+		mgl32.Translate3D(0, 0, 1),
+		mgl32.Translate3D(0, 0.5, 1).Mul4(mgl32.HomogRotate3DZ(0.4)),
+		mgl32.Translate3D(1, 0, 1).Mul4(mgl32.HomogRotate3DZ(0.8)),
+	}
+}
+
+func childKeys() []mgl32.Mat4 {
+	return []mgl32.Mat4{
+		mgl32.Translate3D(0, 1, 0),
+		mgl32.Translate3D(0, 1, 0).Mul4(mgl32.HomogRotate3DX(1.2)),
+	}
+}
+
+func twoBoneAnimations() string {
+	root := testSampler{mode: "LINEAR", times: []float32{0.5, 1, 1.5}, outputs: matrixFloats(rootKeys()...)}
+	child := testSampler{mode: "LINEAR", times: []float32{0.5, 1.5}, outputs: matrixFloats(childKeys()...)}
+	object := testSampler{mode: "LINEAR", times: []float32{0, 2}, outputs: matrixFloats(mgl32.Ident4(), mgl32.Translate3D(0, 3, 0))}
+	return root.animation("Armature_Bone_pose_matrix", "Armature_Bone/transform") +
+		child.animation("Armature_Bone_001_pose_matrix", "Armature_Bone_001/transform") +
+		object.animation("Cube_location", "Cube/transform")
+}
+
+const twoBoneClips = `
+  <library_animation_clips>
+    <animation_clip id="Armature_Walk" name="Walk" start="0.5" end="1.5">
+      <instance_animation url="#Armature_Bone_pose_matrix"/>
+      <instance_animation url="#Armature_Bone_001_pose_matrix"/>
+    </animation_clip>
+  </library_animation_clips>`
+
+func TestExtractAnimations(t *testing.T) {
+	coll, skeleton := parseDocument(t, armatureDocument(twoBoneAnimations(), twoBoneClips))
+	if skeleton.RootIndex != 1 || skeleton.Bones[1].Name != "Bone" || skeleton.Bones[0].Name != "Bone_001" {
+		t.Fatalf("expected Bone at index 1 as the root and Bone_001 at 0, got %+v", skeleton)
+	}
+	animations, err := extractAnimations(coll, skeleton)
+	if err != nil {
+		t.Fatalf("collada test: %v", err)
+	}
+	if len(animations) != 1 || animations[0].Name != "Walk" {
+		t.Fatalf("expected the Walk clip only, got %+v", animations)
+	}
+	animation := animations[0]
+	if animation.Duration != 1 {
+		t.Errorf("expected the clip to last from start to end, got %v", animation.Duration)
+	}
+	//The object channel is not part of the clip, the bone channels are matched by joint name
+	if len(animation.Tracks) != 2 || animation.Tracks[0].BoneIndex != 1 || animation.Tracks[1].BoneIndex != 0 {
+		t.Fatalf("expected tracks for bones 1 and 0, got %+v", animation.Tracks)
+	}
+	expectedTimes := [][]float32{{0, 0.5, 1}, {0, 1}}
+	for i, track := range animation.Tracks {
+		if fmt.Sprint(track.Rotation.Times) != fmt.Sprint(expectedTimes[i]) {
+			t.Errorf("track %v: expected key times shifted by the clip start to %v, got %v", i, expectedTimes[i], track.Rotation.Times)
+		}
+	}
+
+	//At every key the joints are where the authored matrices put them
+	roots, children := rootKeys(), childKeys()
+	tests := []struct {
+		time        float32
+		root, child mgl32.Mat4
+	}{
+		{0, roots[0], children[0]},
+		//The child is halfway between its keys
+		{0.5, roots[1], mgl32.Translate3D(0, 1, 0).Mul4(mgl32.HomogRotate3DX(0.6))},
+		{1, roots[2], children[1]},
+	}
+	for _, test := range tests {
+		world := sampleWorld(t, skeleton, animations, test.time)
+		expectedRoot := testArmatureMatrix.Mul4(test.root)
+		expectedChild := expectedRoot.Mul4(test.child)
+		if !matrixNear(world[1], expectedRoot) {
+			t.Errorf("time %v: expected the root at %v, got %v", test.time, expectedRoot, world[1])
+		}
+		if !matrixNear(world[0], expectedChild) {
+			t.Errorf("time %v: expected the child at %v, got %v", test.time, expectedChild, world[0])
+		}
+	}
+}
+
+func TestExtractAnimationsErrors(t *testing.T) {
+	tests := []struct {
+		name, animations, clips string
+	}{
+		{"unknown clip animation", twoBoneAnimations(), strings.Replace(twoBoneClips, "#Armature_Bone_001_pose_matrix", "#Armature_Bone_002_pose_matrix", 1)},
+		{"location target", testSampler{mode: "LINEAR", times: []float32{0}, outputs: matrixFloats(mgl32.Ident4())}.animation("Armature_Bone_location", "Armature_Bone/location"), ""},
+		{"unsupported interpolation", testSampler{mode: "CARDINAL", times: []float32{0}, outputs: matrixFloats(mgl32.Ident4())}.animation("Armature_Bone_pose_matrix", "Armature_Bone/transform"), ""},
+	}
+	for _, test := range tests {
+		coll, skeleton := parseDocument(t, armatureDocument(test.animations, test.clips))
+		if animations, err := extractAnimations(coll, skeleton); err == nil {
+			t.Errorf("%v: expected an error, got %+v", test.name, animations)
+		}
+	}
+
+	//Object animations alone give no clip
+	object := testSampler{mode: "LINEAR", times: []float32{0, 2}, outputs: matrixFloats(mgl32.Ident4(), mgl32.Translate3D(0, 3, 0))}
+	coll, skeleton := parseDocument(t, armatureDocument(object.animation("Cube_location", "Cube/transform"), ""))
+	if animations, err := extractAnimations(coll, skeleton); err != nil || animations != nil {
+		t.Errorf("expected no animations and no error, got %+v and %v", animations, err)
+	}
+}
+
+// translateX keys move the root along x only, the bone space conversion is
+// affine so the sampled x follows the authored curve
+func translateX(x float32) mgl32.Mat4 {
+	return mgl32.Translate3D(x, 0, 0)
+}
+
+// slopeX is the derivative of translateX
+func slopeX(slope float32) mgl32.Mat4 {
+	var m mgl32.Mat4
+	m[12] = slope
+	return m
+}
+
+// controlX writes a Bezier control point of translateX(x) at time for every
+// matrix element
+func controlX(time, x float32) []float32 {
+	var floats []float32
+	for _, value := range matrixFloats(translateX(x)) {
+		floats = append(floats, time, value)
+	}
+	return floats
+}
+
+func readTestSampler(t *testing.T, s testSampler) (*matrixSampler, error) {
+	t.Helper()
+	var a animation
+	if err := xml.Unmarshal([]byte(s.animation("Bone_pose_matrix", "Armature_Bone/transform")), &a); err != nil {
+		t.Fatalf("collada test: %v", err)
+	}
+	return readMatrixSampler(&a, "Bone_pose_matrix-sampler")
+}
+
+func TestInterpolationModes(t *testing.T) {
+	//The unused tangents at the ends of the curve are far off to show they are ignored
+	tests := []struct {
+		name     string
+		sampler  testSampler
+		expected float32
+	}{
+		{"step", testSampler{mode: "STEP", times: []float32{0, 1}, outputs: matrixFloats(translateX(0), translateX(2))}, 0},
+		{"linear", testSampler{mode: "LINEAR", times: []float32{0, 1}, outputs: matrixFloats(translateX(0), translateX(2))}, 1},
+		//h10*dt*3 + h01*1 at the middle of the segment
+		{"hermite", testSampler{
+			mode: "HERMITE", times: []float32{0, 1}, outputs: matrixFloats(translateX(0), translateX(1)),
+			inTangents: matrixFloats(slopeX(7), slopeX(0)), outTangents: matrixFloats(slopeX(3), slopeX(7)), inStride: 16, outStride: 16,
+		}, 0.875},
+		//Control points a third of the way along keep time linear in the curve parameter
+		{"bezier", testSampler{
+			mode: "BEZIER", times: []float32{0, 1}, outputs: matrixFloats(translateX(0), translateX(1)),
+			inTangents:  append(controlX(-1.0/3, 5), controlX(2.0/3, 1)...),
+			outTangents: append(controlX(1.0/3, 1), controlX(4.0/3, 5)...), inStride: 32, outStride: 32,
+		}, 0.875},
+	}
+	for _, test := range tests {
+		coll, skeleton := parseDocument(t, armatureDocument(test.sampler.animation("Armature_Bone_pose_matrix", "Armature_Bone/transform"), ""))
+		animations, err := extractAnimations(coll, skeleton)
+		if err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+		world := sampleWorld(t, skeleton, animations, 0.5)
+		if expected := testArmatureMatrix.Mul4(translateX(test.expected)); !matrixNear(world[1], expected) {
+			t.Errorf("%v: expected the root at x %v, got %v", test.name, test.expected, world[1])
+		}
+	}
+}
+
+func TestReadMatrixSampler(t *testing.T) {
+	hermite, err := readTestSampler(t, testSampler{
+		mode: "HERMITE", times: []float32{0, 1}, outputs: matrixFloats(translateX(0), translateX(1)),
+		inTangents: matrixFloats(slopeX(1), slopeX(2)), outTangents: matrixFloats(slopeX(3), slopeX(4)), inStride: 16, outStride: 16,
+	})
+	if err != nil {
+		t.Fatalf("collada test: %v", err)
+	}
+	if hermite.interpolation != anim.InterpolationHermite || hermite.matrices[1] != translateX(1) {
+		t.Errorf("expected Hermite keys, got %+v", hermite)
+	}
+	if hermite.inTangents[1] != slopeX(2) || hermite.outTangents[0] != slopeX(3) || hermite.inTimes != nil {
+		t.Errorf("expected the tangents as column major matrices, got %v and %v", hermite.inTangents, hermite.outTangents)
+	}
+
+	bezier, err := readTestSampler(t, testSampler{
+		mode: "BEZIER", times: []float32{0, 1}, outputs: matrixFloats(translateX(0), translateX(1)),
+		inTangents:  append(controlX(-0.25, 0), controlX(0.5, 2)...),
+		outTangents: append(controlX(0.25, 3), controlX(1.25, 1)...), inStride: 32, outStride: 32,
+	})
+	if err != nil {
+		t.Fatalf("collada test: %v", err)
+	}
+	if bezier.inTangents[1] != translateX(2) || bezier.outTangents[0] != translateX(3) {
+		t.Errorf("expected the control point values, got %v and %v", bezier.inTangents, bezier.outTangents)
+	}
+	if fmt.Sprint(bezier.inTimes, bezier.outTimes) != fmt.Sprint([]float32{-0.25, 0.5}, []float32{0.25, 1.25}) {
+		t.Errorf("expected the control point times, got %v and %v", bezier.inTimes, bezier.outTimes)
+	}
+
+	//Control times of elements keyed separately are averaged
+	floats := controlX(0.5, 0)
+	floats[0] = 2.1
+	if times := readControlTimes(floats, 1); mgl32.Abs(times[0]-0.6) > 1e-5 {
+		t.Errorf("expected the average control time 0.6, got %v", times)
+	}
+}
+
+func TestReadMatrixSamplerErrors(t *testing.T) {
+	outputs := matrixFloats(translateX(0), translateX(1))
+	tests := []struct {
+		name    string
+		sampler testSampler
+	}{
+		{"mismatched strides", testSampler{mode: "HERMITE", times: []float32{0, 1}, outputs: outputs,
+			inTangents: matrixFloats(slopeX(1), slopeX(1)), outTangents: append(controlX(0, 0), controlX(1, 1)...), inStride: 16, outStride: 32}},
+		{"bezier without control times", testSampler{mode: "BEZIER", times: []float32{0, 1}, outputs: outputs,
+			inTangents: outputs, outTangents: outputs, inStride: 16, outStride: 16}},
+		{"missing tangent", testSampler{mode: "HERMITE", times: []float32{0, 1}, outputs: outputs,
+			inTangents: matrixFloats(slopeX(1)), outTangents: matrixFloats(slopeX(1)), inStride: 16, outStride: 16}},
+		{"missing output", testSampler{mode: "LINEAR", times: []float32{0, 1}, outputs: matrixFloats(translateX(0))}},
+	}
+	for _, test := range tests {
+		if result, err := readTestSampler(t, test.sampler); err == nil {
+			t.Errorf("%v: expected an error, got %+v", test.name, result)
+		}
+	}
+}
+
+func TestTransformSlope(t *testing.T) {
+	local := translateX(1)
+	//Turning about z at one radian per second while moving along x at two
+	slope := slopeX(2)
+	slope[1], slope[4] = 1, -1
+	result := transformSlope(anim.Mat4ToTransform, local, slope)
+	if mgl32.Vec3(result.Translate).Sub(mgl32.Vec3{2, 0, 0}).Len() > 1e-2 || mgl32.Vec3(result.Scale).Len() > 1e-2 {
+		t.Errorf("expected a slope of 2 along x and no scaling, got %+v", result)
+	}
+	if expected := (mgl32.Quat{W: 0, V: mgl32.Vec3{0, 0, 0.5}}); result.Rotation.V.Sub(expected.V).Len() > 1e-2 || mgl32.Abs(result.Rotation.W) > 1e-2 {
+		t.Errorf("expected the rotation to change by %v per second, got %v", expected, result.Rotation)
+	}
+}
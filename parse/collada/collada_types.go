@@ -7,6 +7,8 @@ type collada struct {
 	LibraryGeometries   *libraryGeometries   `xml:"library_geometries"`
 	LibraryControllers  *libraryControllers  `xml:"library_controllers"`
 	LibraryVisualScenes *libraryVisualScenes `xml:"library_visual_scenes"`
+	LibraryAnimations   *libraryAnimations   `xml:"library_animations"`
+	LibraryClips        *libraryClips        `xml:"library_animation_clips"`
 }
 
 //------library_geometries-----------
@@ -125,3 +127,46 @@ type vertexWeights struct {
 	VCount string  `xml:"vcount"`
 	V      string  `xml:"v"`
 }
+
+//--------library_animations-------------
+
+type libraryAnimations struct {
+	Animations []animation `xml:"animation"`
+}
+
+type animation struct {
+	Id         string      `xml:"id,attr"`
+	Name       string      `xml:"name,attr"`
+	Animations []animation `xml:"animation"`
+	Sources    []source    `xml:"source"`
+	Samplers   []sampler   `xml:"sampler"`
+	Channels   []channel   `xml:"channel"`
+}
+
+type sampler struct {
+	Id     string  `xml:"id,attr"`
+	Inputs []input `xml:"input"`
+}
+
+type channel struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+//--------library_animation_clips-------------
+
+type libraryClips struct {
+	Clips []animationClip `xml:"animation_clip"`
+}
+
+type animationClip struct {
+	Id                 string              `xml:"id,attr"`
+	Name               string              `xml:"name,attr"`
+	Start              string              `xml:"start,attr"`
+	End                string              `xml:"end,attr"`
+	InstanceAnimations []instanceAnimation `xml:"instance_animation"`
+}
+
+type instanceAnimation struct {
+	Url string `xml:"url,attr"`
+}
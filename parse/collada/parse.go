@@ -75,6 +75,49 @@ func ParseMeshSkeleton(fileName string) (*types.Mesh, *anim.Skeleton, error, err
 	if err != nil {
 		return nil, nil, err, err
 	}
+	return parseMeshSkeleton(collada, fileName)
+}
+
+// ParseMeshSkeletonAnimations additionally returns the clips found in the file's
+// library_animations, the second error covers both skeleton and animation data
+func ParseMeshSkeletonAnimations(fileName string) (*types.Mesh, *anim.Skeleton, []anim.Animation, error, error) {
+	collada, err := Parse(fileName)
+	if err != nil {
+		return nil, nil, nil, err, err
+	}
+	mesh, skeleton, err0, err1 := parseMeshSkeleton(collada, fileName)
+	if err0 != nil || err1 != nil {
+		return mesh, skeleton, nil, err0, err1
+	}
+	animations, err := extractAnimations(collada, skeleton)
+	if err != nil {
+		return mesh, skeleton, nil, nil, fmt.Errorf("collada: error extracting animations: %v", err)
+	}
+	return mesh, skeleton, animations, nil, nil
+}
+
+// ParseSkeletonAnimations reads only the rig and its clips, it does not upload
+// any mesh data and can be used without a GL context
+func ParseSkeletonAnimations(fileName string) (*anim.Skeleton, []anim.Animation, error) {
+	collada, err := Parse(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if collada.LibraryControllers == nil || len(collada.LibraryControllers.Controllers) == 0 {
+		return nil, nil, fmt.Errorf("collada: no skin data found in: %v", fileName)
+	}
+	skeleton, err := extractSkeleton(&collada.LibraryControllers.Controllers[0].Skin, collada.LibraryVisualScenes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("collada: error extracting skeleton: %v", err)
+	}
+	animations, err := extractAnimations(collada, skeleton)
+	if err != nil {
+		return skeleton, nil, fmt.Errorf("collada: error extracting animations: %v", err)
+	}
+	return skeleton, animations, nil
+}
+
+func parseMeshSkeleton(collada *collada, fileName string) (*types.Mesh, *anim.Skeleton, error, error) {
 	if collada.LibraryGeometries == nil {
 		return nil, nil, fmt.Errorf("collada to mesh: no geometry data found in %v\n", fileName), nil
 	}
@@ -202,18 +245,16 @@ func extractSkeleton(skin *skin, libraryVisualScenes *libraryVisualScenes) (*ani
 		sidToIndex[name] = i
 	}
 
-	var armature node
-	for _, node := range libraryVisualScenes.VisualScene.Nodes {
-		if node.Name == "Armature" {
-			armature = node
-		}
+	armature := findArmature(libraryVisualScenes)
+	if armature == nil {
+		return nil, fmt.Errorf("collada: no armature found in skeleton data")
 	}
 	for _, node := range armature.Nodes {
 		if node.Type == "JOINT" {
 			bones := make([]anim.Bone, len(boneNames))
 			registerBoneAndItsChildren(bones, &node, sidToIndex[node.Name], bindShapeMatrix, sidToIndex, sidToInvBindMat)
 
-			skeleton := anim.Skeleton{bones, bindShapeMatrix, sidToIndex[node.Sid]}
+			skeleton := anim.Skeleton{Bones: bones, BindShapeMatrix: bindShapeMatrix, RootIndex: sidToIndex[node.Sid]}
 			return &skeleton, nil
 		}
 	}
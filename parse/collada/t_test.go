@@ -3,36 +3,35 @@ package collada
 import (
 	"encoding/xml"
 	"fmt"
-	"log"
+	"os"
 	"testing"
 )
 
+const testModel = "../../data/model/t_baboon.dae"
+
 func TestParse(t *testing.T) {
-	coll, err := Parse("../../data/model/t_baboon.dae")
+	if _, err := os.Stat(testModel); err != nil {
+		t.Skipf("collada test: %v", err)
+	}
+	coll, err := Parse(testModel)
 	if err != nil {
-		log.Fatalf("collada test: %v", err)
+		t.Fatalf("collada test: %v", err)
 	}
 	backInXml, err := xml.MarshalIndent(coll.LibraryControllers, "", "   ")
 	if err != nil {
-		log.Fatalf("collada test: marshalling back to xml error %v", err)
+		t.Fatalf("collada test: marshalling back to xml error %v", err)
 	}
 	fmt.Println(string(backInXml))
 }
 
-/*
-func TestParseToMesh(t *testing.T) {
-	mesh, err := ParseToMesh("/../../data/model/t.dae")
-	if err != nil {
-		log.Fatalf("Collada test: %v\n", err)
+func BenchmarkParse(b *testing.B) {
+	if _, err := os.Stat(testModel); err != nil {
+		b.Skipf("collada test: %v", err)
 	}
-	fmt.Printf("%#v", mesh)
-}
-*/
-func BenchmarkParseToMesh(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_, err := ParseToMesh("t.dae")
+		_, err := Parse(testModel)
 		if err != nil {
-			log.Fatalf("Collada test: %v\n", err)
+			b.Fatalf("Collada test: %v\n", err)
 		}
 	}
 }
@@ -1,9 +1,19 @@
 #version 330
 
+//MAX_BONES is defined by the program loader to fit the skeleton
+#ifndef MAX_BONES
+#define MAX_BONES 15
+#endif
+
+//The bone palette is uploaded as a uniform buffer
+layout (std140) uniform bone_palette
+{
+	mat4 bone_mat[MAX_BONES];
+};
+
 uniform mat4 vp_mat;
 uniform mat4 model_mat;
 uniform mat4 model_rotation_mat;
-uniform mat4 bone_mat[15];
 uniform int  edit_bone;
 
 layout (location = 0) in vec3 position;
@@ -24,8 +34,9 @@ vec4 red   = vec4(1, 0, 0, 1);
 void main()
 {
 	vec3 Weights = weights / ((weights.x + weights.y + weights.z != 0) ? weights.x+weights.y+weights.z : 1);
-	Position = (model_mat * (Weights.x*bone_mat[int(bones.x)] + Weights.y*bone_mat[int(bones.y)] + Weights.z*bone_mat[int(bones.z)]) * vec4(position, 1.0)).xyz;
-	Normal = normalize((model_rotation_mat * (transpose(inverse(Weights.x*bone_mat[int(bones.x)] + Weights.y*bone_mat[int(bones.y)]))) * vec4(normal, 0.0)).xyz);
+	mat4 skin = Weights.x*bone_mat[int(bones.x)] + Weights.y*bone_mat[int(bones.y)] + Weights.z*bone_mat[int(bones.z)];
+	Position = (model_mat * skin * vec4(position, 1.0)).xyz;
+	Normal = normalize((model_rotation_mat * transpose(inverse(skin)) * vec4(normal, 0.0)).xyz);
 	TexCoord = texCoord;
 	Color = white;
 	if(int(bones.x) == edit_bone) {
@@ -37,5 +48,5 @@ void main()
 	if(int(bones.z) == edit_bone) {
 		Color = Weights.z*red + (1-Weights.z)*white;
 	}
-	gl_Position = vp_mat * model_mat * (Weights.x*bone_mat[int(bones.x)] + Weights.y*bone_mat[int(bones.y)] + Weights.z*bone_mat[int(bones.z)]) * vec4(position, 1);
+	gl_Position = vp_mat * vec4(Position, 1);
 }
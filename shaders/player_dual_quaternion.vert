@@ -0,0 +1,72 @@
+#version 330
+
+//MAX_BONES is defined by the program loader to fit the skeleton
+#ifndef MAX_BONES
+#define MAX_BONES 15
+#endif
+
+//The bone palette is uploaded as a uniform buffer
+layout (std140) uniform bone_palette
+{
+	mat2x4 bone_dq[MAX_BONES];
+};
+
+uniform mat4   vp_mat;
+uniform mat4   model_mat;
+uniform mat4   model_rotation_mat;
+uniform int    edit_bone;
+
+layout (location = 0) in vec3 position;
+layout (location = 1) in vec3 normal;
+layout (location = 2) in vec2 texCoord;
+layout (location = 3) in vec3 color;
+layout (location = 4) in vec3 bones;
+layout (location = 5) in vec3 weights;
+
+out vec3 Normal;
+out vec3 Position;
+out vec2 TexCoord;
+out vec4 Color;
+
+vec4 white = vec4(1, 1, 1, 1);
+vec4 red   = vec4(1, 0, 0, 1);
+
+void main()
+{
+	vec3 Weights = weights / ((weights.x + weights.y + weights.z != 0) ? weights.x+weights.y+weights.z : 1);
+	mat2x4 dq0 = bone_dq[int(bones.x)];
+	mat2x4 dq1 = bone_dq[int(bones.y)];
+	mat2x4 dq2 = bone_dq[int(bones.z)];
+	//Keep every rotation in the hemisphere of the first so the blend takes the short way around
+	if(dot(dq0[0], dq1[0]) < 0) {
+		dq1 = -dq1;
+	}
+	if(dot(dq0[0], dq2[0]) < 0) {
+		dq2 = -dq2;
+	}
+	mat2x4 dq = Weights.x*dq0 + Weights.y*dq1 + Weights.z*dq2;
+	dq /= length(dq[0]);
+
+	//The quaternions are stored as (w, x, y, z)
+	float rw = dq[0].x;
+	vec3  r  = dq[0].yzw;
+	float dw = dq[1].x;
+	vec3  d  = dq[1].yzw;
+	vec3 skinnedPosition = position + 2*cross(r, cross(r, position) + rw*position) + 2*(rw*d - dw*r + cross(r, d));
+	vec3 skinnedNormal = normal + 2*cross(r, cross(r, normal) + rw*normal);
+
+	Position = (model_mat * vec4(skinnedPosition, 1.0)).xyz;
+	Normal = normalize((model_rotation_mat * vec4(skinnedNormal, 0.0)).xyz);
+	TexCoord = texCoord;
+	Color = white;
+	if(int(bones.x) == edit_bone) {
+		Color = Weights.x*red + (1-Weights.x)*white;
+	}
+	if(int(bones.y) == edit_bone) {
+		Color = Weights.y*red + (1-Weights.y)*white;
+	}
+	if(int(bones.z) == edit_bone) {
+		Color = Weights.z*red + (1-Weights.z)*white;
+	}
+	gl_Position = vp_mat * vec4(Position, 1);
+}
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+	"unsafe"
+
+	"training/engine/anim"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Binding point the bone palette's uniform buffer is bound to
+const BONE_PALETTE_BINDING = 0
+
+// Bytes per bone of a palette, one std140 mat4. Dual quaternions need half
+const boneStride = 16 * 4
+
+// BonePalette is a uniform buffer holding a skeleton's skinning data, read by
+// the skinning shaders as the uniform block bone_palette
+type BonePalette struct {
+	UBO       uint32
+	BoneCount int
+}
+
+// NewBonePalette allocates a palette for boneCount bones, or returns an error
+// when a palette that size does not fit into a uniform block of the driver
+func NewBonePalette(boneCount int) (*BonePalette, error) {
+	if err := CheckBoneCount(boneCount); err != nil {
+		return nil, err
+	}
+	p := &BonePalette{BoneCount: boneCount}
+	gl.GenBuffers(1, &p.UBO)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, p.UBO)
+	gl.BufferData(gl.UNIFORM_BUFFER, boneCount*boneStride, nil, gl.DYNAMIC_DRAW)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+	return p, nil
+}
+
+// CheckBoneCount compares the size of a skeleton's palette with the largest
+// uniform block supported by the driver
+func CheckBoneCount(boneCount int) error {
+	var maxBlockSize int32
+	gl.GetIntegerv(gl.MAX_UNIFORM_BLOCK_SIZE, &maxBlockSize)
+	return checkBoneCount(boneCount, int(maxBlockSize))
+}
+
+func checkBoneCount(boneCount, maxBlockSize int) error {
+	if boneCount <= 0 {
+		return fmt.Errorf("bone palette: skeleton has no bones")
+	}
+	if boneCount*boneStride > maxBlockSize {
+		return fmt.Errorf("bone palette: %v bones need %v bytes, but uniform blocks are limited to %v bytes (%v bones)", boneCount, boneCount*boneStride, maxBlockSize, maxBlockSize/boneStride)
+	}
+	return nil
+}
+
+// MaxBonesDefine specializes the skinning shaders for the palette's size, it
+// is passed to shader.NewProgramFromFiles
+func (p *BonePalette) MaxBonesDefine() string {
+	return fmt.Sprintf("MAX_BONES %v", p.BoneCount)
+}
+
+func (p *BonePalette) UploadMatrices(matrices []mgl32.Mat4) {
+	p.upload(len(matrices), len(matrices)*16*4, gl.Ptr(&matrices[0][0]))
+}
+
+func (p *BonePalette) UploadDualQuaternions(dualQuats []anim.DualQuat) {
+	p.upload(len(dualQuats), len(dualQuats)*8*4, gl.Ptr(&dualQuats[0].Real.W))
+}
+
+func (p *BonePalette) upload(count, size int, data unsafe.Pointer) {
+	if count != p.BoneCount {
+		panic(fmt.Errorf("bone palette: uploading %v bones to a palette of %v", count, p.BoneCount))
+	}
+	gl.BindBuffer(gl.UNIFORM_BUFFER, p.UBO)
+	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, size, data)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+}
+
+// Bind attaches the palette to the shader's bone_palette block
+func (p *BonePalette) Bind(shader uint32) {
+	index := gl.GetUniformBlockIndex(shader, gl.Str("bone_palette\x00"))
+	if index == gl.INVALID_INDEX {
+		return
+	}
+	gl.UniformBlockBinding(shader, index, BONE_PALETTE_BINDING)
+	gl.BindBufferBase(gl.UNIFORM_BUFFER, BONE_PALETTE_BINDING, p.UBO)
+}
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckBoneCount(t *testing.T) {
+	//16KB is the smallest uniform block size OpenGL 3.3 allows
+	if err := checkBoneCount(256, 16384); err != nil {
+		t.Errorf("expected 256 bones to fit, got %v", err)
+	}
+	if err := checkBoneCount(257, 16384); err == nil || !strings.Contains(err.Error(), "(256 bones)") {
+		t.Errorf("expected an error naming the limit, got %v", err)
+	}
+	if err := checkBoneCount(0, 16384); err == nil {
+		t.Errorf("expected an error for an empty skeleton")
+	}
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const bonesPerVertex = 3
+
+// Skin deforms the mesh's positions and normals on the CPU the same way the
+// linear blend skinning shader does: each vertex is moved by the sum of its
+// bone matrices, scaled by weights normalized to add up to one. The bone
+// indices and weights are read from the USE_BONES blocks collada.extractMesh
+// appends, three of each per vertex
+func (m *Mesh) Skin(poseMatrices []mgl32.Mat4) (positions, normals []mgl32.Vec3, err error) {
+	if m.AttrMask&USE_BONES == 0 {
+		return nil, nil, fmt.Errorf("mesh: skinning needs bone data")
+	}
+	if m.AttrMask&USE_POSITIONS == 0 {
+		return nil, nil, fmt.Errorf("mesh: skinning needs positions")
+	}
+	vertexCount := m.vertexCount()
+	positions = make([]mgl32.Vec3, vertexCount)
+	if m.AttrMask&USE_NORMALS != 0 {
+		normals = make([]mgl32.Vec3, vertexCount)
+	}
+	for i := 0; i < vertexCount; i++ {
+		skin, err := m.skinMatrix(i, poseMatrices)
+		if err != nil {
+			return nil, nil, err
+		}
+		positions[i] = skin.Mul4x1(m.attribute(0, i).Vec4(1)).Vec3()
+		if normals != nil {
+			normals[i] = skin.Inv().Transpose().Mul4x1(m.attribute(1, i).Vec4(0)).Vec3().Normalize()
+		}
+	}
+	return positions, normals, nil
+}
+
+// SkinnedBounds returns the corners of the axis aligned box around the mesh
+// deformed by the pose
+func (m *Mesh) SkinnedBounds(poseMatrices []mgl32.Mat4) (low, high mgl32.Vec3, err error) {
+	positions, _, err := m.Skin(poseMatrices)
+	if err != nil {
+		return low, high, err
+	}
+	if len(positions) == 0 {
+		return low, high, nil
+	}
+	low, high = positions[0], positions[0]
+	for _, p := range positions[1:] {
+		for c := 0; c < 3; c++ {
+			if p[c] < low[c] {
+				low[c] = p[c]
+			} else if p[c] > high[c] {
+				high[c] = p[c]
+			}
+		}
+	}
+	return low, high, nil
+}
+
+// BakePose skins the mesh by the pose and returns the vertex data of a static
+// copy without bones, ready to be passed to Init with the mesh's indices
+func (m *Mesh) BakePose(poseMatrices []mgl32.Mat4) (floats []float32, attrMask uint32, offsets [6]int, err error) {
+	positions, normals, err := m.Skin(poseMatrices)
+	if err != nil {
+		return nil, 0, offsets, err
+	}
+	//Cut the bone index and weight blocks out and shift the blocks behind them
+	vertexCount := len(positions)
+	boneStart, boneEnd := m.Offsets[4], m.Offsets[5]+bonesPerVertex*vertexCount
+	if m.Offsets[5] < boneStart {
+		boneStart, boneEnd = m.Offsets[5], m.Offsets[4]+bonesPerVertex*vertexCount
+	}
+	floats = append(append([]float32(nil), m.Floats[:boneStart]...), m.Floats[boneEnd:]...)
+	attrMask = m.AttrMask &^ USE_BONES
+	for i := 0; i < 4; i++ {
+		offsets[i] = m.Offsets[i]
+		if offsets[i] >= boneEnd {
+			offsets[i] -= boneEnd - boneStart
+		}
+	}
+	for i := 0; i < vertexCount; i++ {
+		copy(floats[offsets[0]+3*i:], positions[i][:])
+		if normals != nil {
+			copy(floats[offsets[1]+3*i:], normals[i][:])
+		}
+	}
+	return floats, attrMask, offsets, nil
+}
+
+func (m *Mesh) vertexCount() int {
+	if m.Offsets[5] > m.Offsets[4] {
+		return (m.Offsets[5] - m.Offsets[4]) / bonesPerVertex
+	}
+	return (m.Offsets[4] - m.Offsets[5]) / bonesPerVertex
+}
+
+func (m *Mesh) attribute(index, vertex int) mgl32.Vec3 {
+	start := m.Offsets[index] + 3*vertex
+	return mgl32.Vec3{m.Floats[start], m.Floats[start+1], m.Floats[start+2]}
+}
+
+func (m *Mesh) skinMatrix(vertex int, poseMatrices []mgl32.Mat4) (mgl32.Mat4, error) {
+	bones, weights := m.attribute(4, vertex), m.attribute(5, vertex)
+	total := weights[0] + weights[1] + weights[2]
+	if total == 0 {
+		total = 1
+	}
+	var skin mgl32.Mat4
+	for j := 0; j < bonesPerVertex; j++ {
+		bone := int(bones[j])
+		if bone < 0 || bone >= len(poseMatrices) {
+			return skin, fmt.Errorf("mesh: vertex %v uses bone %v, but the pose has %v", vertex, bone, len(poseMatrices))
+		}
+		skin = skin.Add(poseMatrices[bone].Mul(weights[j] / total))
+	}
+	return skin, nil
+}
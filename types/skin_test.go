@@ -0,0 +1,67 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// skinTestMesh lays out two vertices the way collada.extractMesh does,
+// positions, normals and texture coordinates followed by bone indices and weights
+func skinTestMesh() *Mesh {
+	floats := []float32{
+		1, 0, 0, 0, 1, 0, //positions
+		0, 1, 0, 1, 0, 0, //normals
+		0, 0, 1, 1, //texture coordinates
+		0, 1, 0, 1, 0, 0, //bone indices
+		1, 1, 0, 2, 0, 0, //weights
+	}
+	return &Mesh{Floats: floats, Indices: []uint32{0, 1}, AttrMask: USE_POSITIONS | USE_NORMALS | USE_TEXCOORDS | USE_BONES, Offsets: [6]int{0, 6, 12, 16, 16, 22}}
+}
+
+func near(a, b mgl32.Vec3) bool {
+	return a.Sub(b).Len() < 1e-5
+}
+
+func TestSkinMatchesShader(t *testing.T) {
+	mesh := skinTestMesh()
+	pose := []mgl32.Mat4{mgl32.Translate3D(0, 2, 0), mgl32.HomogRotate3DZ(mgl32.DegToRad(90))}
+	positions, normals, err := mesh.Skin(pose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//The first vertex is split evenly, the second follows the second bone alone
+	expectedPositions := []mgl32.Vec3{{0.5, 1.5, 0}, {-1, 0, 0}}
+	for i := range expectedPositions {
+		if !near(positions[i], expectedPositions[i]) {
+			t.Errorf("vertex %v: expected %v, got %v", i, expectedPositions[i], positions[i])
+		}
+	}
+	if expected := (mgl32.Vec3{0, 1, 0}); !near(normals[1], expected) || !mgl32.FloatEqualThreshold(normals[0].Len(), 1, 1e-5) {
+		t.Errorf("expected unit normals with the second one rotated to %v, got %v", expected, normals)
+	}
+	low, high, err := mesh.SkinnedBounds(pose)
+	if err != nil || !near(low, mgl32.Vec3{-1, 0, 0}) || !near(high, mgl32.Vec3{0.5, 1.5, 0}) {
+		t.Errorf("expected bounds from (-1, 0, 0) to (0.5, 1.5, 0), got %v %v %v", low, high, err)
+	}
+	if _, _, err := mesh.Skin(pose[:1]); err == nil {
+		t.Errorf("expected an error for a pose with too few bones")
+	}
+}
+
+func TestBakePose(t *testing.T) {
+	mesh := skinTestMesh()
+	floats, attrMask, offsets, err := mesh.BakePose([]mgl32.Mat4{mgl32.Translate3D(0, 2, 0), mgl32.Translate3D(0, 0, 4)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrMask&USE_BONES != 0 || len(floats) != 16 || offsets[2] != 12 {
+		t.Fatalf("expected the bone data to be removed, got mask %b, %v floats and offsets %v", attrMask, len(floats), offsets)
+	}
+	expected := []float32{1, 1, 2, 0, 1, 4, 0, 1, 0, 1, 0, 0, 0, 0, 1, 1}
+	for i := range expected {
+		if !mgl32.FloatEqualThreshold(floats[i], expected[i], 1e-5) {
+			t.Fatalf("expected %v, got %v", expected, floats)
+		}
+	}
+}
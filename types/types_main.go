@@ -27,7 +27,15 @@ type Mesh struct {
 	Offsets  [6]int
 }
 
+// Skinning modes of a model
+const (
+	SKIN_LINEAR_BLEND = iota
+	SKIN_DUAL_QUATERNION
+)
+
 type Model struct {
 	Mesh     *Mesh
 	Animator *anim.Animator
+	Skinning int
+	Palette  *BonePalette
 }
@@ -104,274 +104,274 @@ func (f *frameTimer) Update() {
 }
 
 var keyframe00 = anim.Keyframe{SampleTime: 0, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe01 = anim.Keyframe{SampleTime: 0.1, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{0, 0.05, 0}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -5, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-30, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0.05, 0}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -5, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-30, 0, 0})}}
 var keyframe02 = anim.Keyframe{SampleTime: 0.2, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe03 = anim.Keyframe{SampleTime: 0.3, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{0, 0.05, 0}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 5, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-30, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0.05, 0}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 5, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-30, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{15, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0})}}
 var keyframe04 = anim.Keyframe{SampleTime: 0.4, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe10 = anim.Keyframe{SampleTime: 0, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{90, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-90, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{90, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-90, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe11 = anim.Keyframe{SampleTime: 0.1, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{0, 0.025, 0}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 20, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{45, 0, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{130, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -20, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-50, -20, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{60, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -30, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-40, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{80, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-40, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-50, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0.025, 0}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 20, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{45, 0, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{130, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -20, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-50, -20, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{60, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -30, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-40, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{80, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-40, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-50, 0, 0})}}
 var keyframe12 = anim.Keyframe{SampleTime: 0.2, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{90, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-90, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{90, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-90, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0})}}
 var keyframe13 = anim.Keyframe{SampleTime: 0.3, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{0, 0.025, 0}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -20, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 20, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-50, 20, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{60, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{45, 0, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{130, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 30, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{80, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-40, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-50, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-40, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, 0.025, 0}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -20, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 20, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-50, 20, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{60, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{45, 0, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{130, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 30, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{80, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-40, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-50, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-40, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0})}}
 var keyframe14 = anim.Keyframe{SampleTime: 0.4, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{90, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 30}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-90, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{90, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 30}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-90, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe20 = anim.Keyframe{SampleTime: -1, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -60, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -45, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -60, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, -45, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe21 = anim.Keyframe{SampleTime: 1, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 60, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 45, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 60, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 45, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe30 = anim.Keyframe{SampleTime: 0, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -40}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 40}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{0, -0.005, 0}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -40}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 40}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, -0.005, 0}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe31 = anim.Keyframe{SampleTime: 1, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -40}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 40}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1.005, 1.005, 1.005}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -40}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 40}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1.005, 1.005, 1.005}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe32 = anim.Keyframe{SampleTime: 2, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -40}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 40}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{0, -0.005, 0}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -40}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 40}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{0, -0.005, 0}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe40 = anim.Keyframe{SampleTime: 0, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -40}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 40}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -40}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 10}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 40}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{10, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}
 var keyframe41 = anim.Keyframe{SampleTime: 1, Transforms: []anim.Transform{
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 20}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -20}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{100, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-80, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}},
-	anim.Transform{[3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}}}}
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 20}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, -20}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{100, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-80, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{-20, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0}),
+	anim.TransformFromEuler([3]float32{1, 1, 1}, [3]float32{}, [3]float32{0, 0, 0})}}